@@ -10,7 +10,12 @@ import (
 	"zatrano/configs/fileconfig"
 	"zatrano/configs/logconfig"
 	"zatrano/configs/sessionconfig"
+	"zatrano/models"
+	"zatrano/pkg/apperrors"
+	"zatrano/pkg/exporter"
 	"zatrano/pkg/flashmessages"
+	"zatrano/pkg/mailer"
+	"zatrano/pkg/pgenum"
 	"zatrano/pkg/templatehelpers"
 	"zatrano/routes"
 
@@ -33,6 +38,10 @@ func main() {
 	databaseconfig.InitDB()
 	defer databaseconfig.CloseDB()
 
+	if err := pgenum.Validate(databaseconfig.GetDB(), models.Enums()...); err != nil {
+		logconfig.Log.Fatal("Veritabanı şeması kodla uyumlu değil, 'go run ./database/cmd up' çalıştırın", zap.Error(err))
+	}
+
 	sessionconfig.InitSession()
 
 	fileconfig.InitFileConfig()
@@ -40,6 +49,10 @@ func main() {
 	fileconfig.Config.SetAllowedExtensions("post", []string{"jpg", "png", "webp"})
 	fileconfig.Config.SetAllowedExtensions("profile", []string{"jpeg", "png"})
 
+	exporter.InitJobs(fileconfig.Config.GetPath("exports"))
+
+	mailer.InitMailer()
+
 	engine := html.New("./views", ".html")
 	engine.AddFunc("getFlashMessages", flashmessages.GetFlashMessages)
 	engine.AddFuncMap(templatehelpers.TemplateHelpers())
@@ -49,10 +62,18 @@ func main() {
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 			code := fiber.StatusInternalServerError
 			message := "Internal Server Error"
+			body := fiber.Map{}
 
 			if e, ok := err.(*fiber.Error); ok {
 				code = e.Code
 				message = e.Message
+			} else if appErr, ok := apperrors.As(err); ok {
+				code = apperrors.HTTPStatus(err)
+				message = apperrors.Message(err)
+				body["kind"] = appErr.Kind
+				if len(appErr.Fields) > 0 {
+					body["fields"] = appErr.Fields
+				}
 			}
 
 			logconfig.Log.Error("Fiber request error",
@@ -63,7 +84,8 @@ func main() {
 				zap.String("ip", c.IP()),
 			)
 
-			return c.Status(code).JSON(fiber.Map{"error": message})
+			body["error"] = message
+			return c.Status(code).JSON(body)
 		},
 	})
 
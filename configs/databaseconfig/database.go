@@ -1,23 +1,41 @@
 package databaseconfig
 
 import (
+	"database/sql"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"zatrano/configs/envconfig"
 	"zatrano/configs/logconfig"
+	"zatrano/pkg/history"
+	"zatrano/pkg/replica"
+	"zatrano/pkg/turkishsearch"
 
+	"github.com/glebarez/sqlite"
+	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/joho/godotenv"
 	"go.uber.org/zap"
+	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
 )
 
 var DB *gorm.DB
 
+// Desteklenen DB_DRIVER değerleri; gorm dialector adlarıyla aynıdır.
+const (
+	DriverPostgres = "postgres"
+	DriverMySQL    = "mysql"
+	DriverSQLite   = "sqlite"
+)
+
 type DatabaseConfig struct {
+	Driver   string
 	Host     string
 	Port     int
 	User     string
@@ -35,7 +53,17 @@ func InitDB() {
 		logconfig.SLog.Info(".env dosyası başarıyla yüklendi")
 	}
 
-	portStr := envconfig.GetEnvWithDefault("DB_PORT", "5432")
+	driver := envconfig.GetEnvWithDefault("DB_DRIVER", DriverPostgres)
+	defaultPort := "5432"
+	switch driver {
+	case DriverPostgres, DriverSQLite:
+	case DriverMySQL:
+		defaultPort = "3306"
+	default:
+		logconfig.SLog.Fatalw("Desteklenmeyen DB_DRIVER değeri (postgres, mysql, sqlite)", "value", driver)
+	}
+
+	portStr := envconfig.GetEnvWithDefault("DB_PORT", defaultPort)
 	port, err := strconv.Atoi(portStr)
 	if err != nil {
 		logconfig.SLog.Fatalw("Invalid DB_PORT environment variable",
@@ -45,6 +73,7 @@ func InitDB() {
 	}
 
 	dbConfig := DatabaseConfig{
+		Driver:   driver,
 		Host:     envconfig.GetEnvWithDefault("DB_HOST", "localhost"),
 		Port:     port,
 		User:     envconfig.GetEnvWithDefault("DB_USERNAME", "postgres"),
@@ -55,6 +84,7 @@ func InitDB() {
 	}
 
 	logconfig.Log.Info("Database configuration loaded",
+		zap.String("driver", dbConfig.Driver),
 		zap.String("host", dbConfig.Host),
 		zap.Int("port", dbConfig.Port),
 		zap.String("user", dbConfig.User),
@@ -63,16 +93,8 @@ func InitDB() {
 		zap.String("timezone", dbConfig.TimeZone),
 	)
 
-	dsn := "host=" + dbConfig.Host +
-		" user=" + dbConfig.User +
-		" password=" + dbConfig.Password +
-		" dbname=" + dbConfig.Name +
-		" port=" + strconv.Itoa(dbConfig.Port) +
-		" sslmode=" + dbConfig.SSLMode +
-		" TimeZone=" + dbConfig.TimeZone
-
 	var gormerr error
-	DB, gormerr = gorm.Open(postgres.Open(dsn), &gorm.Config{
+	DB, gormerr = gorm.Open(openDialector(dbConfig), &gorm.Config{
 		Logger: logger.Default.LogMode(getGormLogLevel()),
 		NowFunc: func() time.Time {
 			return time.Now().UTC()
@@ -107,6 +129,110 @@ func InitDB() {
 		zap.Int("max_open_conns", maxOpenConns),
 		zap.Int("conn_max_lifetime_minutes", connMaxLifetimeMinutes),
 	)
+
+	if err := history.Register(DB); err != nil {
+		logconfig.Log.Fatal("Değişiklik geçmişi callback'leri kaydedilemedi", zap.Error(err))
+	}
+
+	if err := replica.RegisterWriteTracking(DB); err != nil {
+		logconfig.Log.Fatal("Yazma izleme callback'leri kaydedilemedi", zap.Error(err))
+	}
+	setupReplicas(driver, maxIdleConns, maxOpenConns, time.Duration(connMaxLifetimeMinutes)*time.Minute)
+
+	if err := turkishsearch.Detect(DB); err != nil {
+		logconfig.Log.Warn("Arama eklentileri algılanamadı, translate() ile devam ediliyor", zap.Error(err))
+	}
+}
+
+// openDialector DB_DRIVER'a göre bağlantı dialector'ını oluşturur. SQLite'ta
+// DB_DATABASE dosya yolu olarak kullanılır; sunucu ayarları yok sayılır.
+func openDialector(cfg DatabaseConfig) gorm.Dialector {
+	// İşlem dışındaki sorgular için varsayılan üst sınır; PostgreSQL'de işlemler
+	// txmanager'da isteğin kalan süresine göre SET LOCAL ile daraltılır.
+	statementTimeoutMs := envconfig.GetEnvAsInt("DB_STATEMENT_TIMEOUT_MS", 15000)
+
+	switch cfg.Driver {
+	case DriverSQLite:
+		// SQLite aynı anda tek yazıcıya izin verir: WAL okumaların yazmayı beklemesini
+		// önler, işlemler yazma kilidini baştan alır ve kilit için busy_timeout kadar beklenir.
+		return sqlite.Open(cfg.Name + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate")
+	case DriverMySQL:
+		dsn := cfg.User + ":" + cfg.Password +
+			"@tcp(" + cfg.Host + ":" + strconv.Itoa(cfg.Port) + ")/" + cfg.Name +
+			"?charset=utf8mb4&parseTime=True&multiStatements=true&loc=" + url.QueryEscape(cfg.TimeZone)
+		if statementTimeoutMs > 0 {
+			// MySQL'de yalnızca SELECT sorgularını sınırlar.
+			dsn += "&max_execution_time=" + strconv.Itoa(statementTimeoutMs)
+		}
+		return mysql.Open(dsn)
+	default:
+		dsn := "host=" + cfg.Host +
+			" user=" + cfg.User +
+			" password=" + cfg.Password +
+			" dbname=" + cfg.Name +
+			" port=" + strconv.Itoa(cfg.Port) +
+			" sslmode=" + cfg.SSLMode +
+			" TimeZone=" + cfg.TimeZone
+		if statementTimeoutMs > 0 {
+			dsn += " statement_timeout=" + strconv.Itoa(statementTimeoutMs)
+		}
+		return postgres.Open(dsn)
+	}
+}
+
+// setupReplicas DB_REPLICA_DSNS ile verilen (";" ile ayrılmış) okuma kopyalarını
+// dbresolver'a kaydeder. Bağlantı havuzu ayarları birincil veritabanıyla aynıdır.
+// DSN'ler birincil veritabanıyla aynı sürücünün biçiminde olmalıdır.
+func setupReplicas(driver string, maxIdleConns, maxOpenConns int, connMaxLifetime time.Duration) {
+	var dsns []string
+	for _, dsn := range strings.Split(os.Getenv("DB_REPLICA_DSNS"), ";") {
+		if dsn = strings.TrimSpace(dsn); dsn != "" {
+			dsns = append(dsns, dsn)
+		}
+	}
+	if len(dsns) == 0 {
+		return
+	}
+	if driver == DriverSQLite {
+		logconfig.SLog.Warn("SQLite okuma kopyalarını desteklemiyor, DB_REPLICA_DSNS yok sayılıyor.")
+		return
+	}
+
+	pools := make([]*sql.DB, 0, len(dsns))
+	dialectors := make([]gorm.Dialector, 0, len(dsns))
+	for i, dsn := range dsns {
+		sqlDriver := "pgx"
+		if driver == DriverMySQL {
+			sqlDriver = "mysql"
+		}
+		pool, err := sql.Open(sqlDriver, dsn)
+		if err != nil {
+			logconfig.Log.Fatal("Okuma kopyası yapılandırması geçersiz", zap.Int("replica", i), zap.Error(err))
+		}
+		pool.SetMaxIdleConns(maxIdleConns)
+		pool.SetMaxOpenConns(maxOpenConns)
+		pool.SetConnMaxLifetime(connMaxLifetime)
+		pools = append(pools, pool)
+		if driver == DriverMySQL {
+			dialectors = append(dialectors, mysql.New(mysql.Config{Conn: pool}))
+		} else {
+			dialectors = append(dialectors, postgres.New(postgres.Config{Conn: pool}))
+		}
+	}
+
+	monitor := replica.NewMonitor(pools)
+	if err := DB.Use(dbresolver.Register(dbresolver.Config{
+		Replicas: dialectors,
+		Policy:   monitor.Policy(),
+	})); err != nil {
+		logconfig.Log.Fatal("Okuma kopyaları kaydedilemedi", zap.Error(err))
+	}
+	monitor.Start(time.Duration(envconfig.GetEnvAsInt("DB_REPLICA_HEALTH_INTERVAL_SECONDS", 10)) * time.Second)
+
+	logconfig.Log.Info("Okuma kopyaları yapılandırıldı",
+		zap.Int("replicas", len(pools)),
+		zap.Duration("read_your_writes_window", replica.ReadYourWritesWindow()),
+	)
 }
 
 func getGormLogLevel() logger.LogLevel {
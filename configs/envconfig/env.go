@@ -26,6 +26,19 @@ func GetEnvAsInt(key string, defaultValue int) int {
 	return valueInt
 }
 
+func GetEnvAsFloat(key string, defaultValue float64) float64 {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	valueFloat, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return defaultValue
+	}
+	return valueFloat
+}
+
 func IsProduction() bool {
 	return os.Getenv("APP_ENV") == "production"
 }
@@ -80,3 +80,11 @@ func GetUserStatusFromSession(sess *session.Session) (bool, error) {
 	}
 	return userStatus, nil
 }
+
+func GetTenantIDFromSession(sess *session.Session) (uint, error) {
+	tenantID, ok := sess.Get("tenant_id").(uint)
+	if !ok || tenantID == 0 {
+		return 0, fiber.NewError(fiber.StatusUnauthorized, "Geçersiz oturum veya kiracı ID'si")
+	}
+	return tenantID, nil
+}
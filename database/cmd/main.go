@@ -1,27 +1,365 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
 
-	"zatrano/configs/database_config"
-	"zatrano/configs/log_config"
+	"zatrano/configs/databaseconfig"
+	"zatrano/configs/logconfig"
 	"zatrano/database"
+	"zatrano/database/seeders"
+	"zatrano/pkg/migrator"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Çıkış kodları dağıtım betiklerinde kullanılabilir: status bekleyen migrasyon varsa
+// exitPending ile döner.
+const (
+	exitOK      = 0
+	exitError   = 1
+	exitUsage   = 2
+	exitPending = 3
 )
 
+const usage = `Kullanım: go run ./database/cmd [-dry-run] <komut> [argümanlar]
+
+Komutlar:
+  status          Uygulanmış ve bekleyen migrasyonları listeler (bekleyen varsa çıkış kodu 3)
+  up [n]          Enum tiplerini eşitler, bekleyen migrasyonların ilk n tanesini,
+                  n verilmezse tümünü uygular
+  down [n]        Son uygulanan n migrasyonu geri alır (varsayılan 1)
+  redo            Son uygulanan migrasyonu geri alıp yeniden uygular
+  create <isim>   database/migrations/sql altında boş up/down SQL dosyaları oluşturur
+  diff <isim>     Modelleri migrasyonların kurduğu şemayla karşılaştırıp farkı çalışan
+                  sürücüye özel up/down SQL dosyalarına yazar; diğer sürücüler için
+                  ayrıca diff alınmalıdır. Dosyalar uygulanmaz, gözden geçirilmelidir.
+                  Yıkıcı adımlar YIKICI işaretiyle yorum satırı olarak yazılır.
+                  -dry-run ile dosya yerine stdout'a yazar
+  seed [isim...]  Seeder'ları çalıştırır; isim verilirse yalnızca onları ve bağımlılıklarını
+                  seed seçenekleri: -env=dev|test|prod (varsayılan APP_ENV'den),
+                  -count=n (sahte kayıt sayısı), -faker-seed=n (tekrarlanabilir sahte veri)
+
+Seçenekler:
+`
+
+var migrationNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
 func main() {
-	log_config.InitLogger()
-	defer log_config.SyncLogger()
-	migrateFlag := flag.Bool("migrate", false, "Veritabanı başlatma işlemini çalıştır (migrasyonları içerir)")
-	seedFlag := flag.Bool("seed", false, "Veritabanı başlatma işlemini çalıştır (seederları içerir)")
+	os.Exit(run())
+}
+
+func run() int {
+	dryRun := flag.Bool("dry-run", false, "SQL'i çalıştırmadan yazdırır (up, down, redo)")
+	dir := flag.String("dir", "database/migrations/sql", "create komutunun dosyaları oluşturacağı dizin")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
-	database_config.InitDB()
-	defer database_config.CloseDB()
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		return exitUsage
+	}
+	command, args := args[0], args[1:]
+
+	if command == "create" || command == "diff" {
+		if len(args) != 1 || !migrationNamePattern.MatchString(args[0]) {
+			fmt.Fprintf(os.Stderr, "%s için küçük harf, rakam ve alt çizgiden oluşan bir isim gerekli (ör. add_users_phone)\n", command)
+			return exitUsage
+		}
+		if command == "create" {
+			return createMigration(*dir, args[0])
+		}
+	}
+
+	var seedOpts seeders.Options
+	var seedNames []string
+	limit, ok := 0, true
+	switch command {
+	case "seed":
+		seedOpts, seedNames, ok = parseSeedArgs(args)
+	case "diff":
+	default:
+		limit, ok = parseLimit(command, args)
+	}
+	if !ok {
+		flag.Usage()
+		return exitUsage
+	}
+
+	logconfig.InitLogger()
+	defer logconfig.SyncLogger()
+
+	databaseconfig.InitDB()
+	defer databaseconfig.CloseDB()
+	db := databaseconfig.GetDB()
+	if *dryRun || command == "status" || command == "diff" {
+		// gorm sorgu günlükleri stdout'a yazılır; çıktı yalnızca tablo ya da SQL olsun.
+		db = db.Session(&gorm.Session{Logger: db.Logger.LogMode(logger.Silent)})
+	}
+
+	if command == "seed" {
+		if err := database.RunSeeders(context.Background(), db, seedOpts, seedNames...); err != nil {
+			fmt.Fprintln(os.Stderr, "Hata:", err)
+			return exitError
+		}
+		return exitOK
+	}
+
+	m, err := database.NewMigrator(db)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Migrasyonlar yüklenemedi:", err)
+		return exitError
+	}
+	if command == "diff" {
+		return diffSchema(context.Background(), db, m, *dir, args[0], *dryRun)
+	}
+	if *dryRun {
+		m = m.DryRun(os.Stdout)
+	}
+
+	ctx := context.Background()
+	switch command {
+	case "status":
+		return printStatus(ctx, m)
+	case "up":
+		if err := database.SyncEnums(ctx, m); err != nil {
+			fmt.Fprintln(os.Stderr, "Hata:", err)
+			return exitError
+		}
+		n, err := m.Up(ctx, limit)
+		return report(n, "migrasyon uygulandı", err)
+	case "down":
+		n, err := m.Down(ctx, limit)
+		return report(n, "migrasyon geri alındı", err)
+	default: // redo
+		mig, err := m.Redo(ctx)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Hata:", err)
+			return exitError
+		}
+		fmt.Fprintf(os.Stderr, "%s yeniden uygulandı\n", mig)
+		return exitOK
+	}
+}
+
+// parseLimit komutu ve up/down için isteğe bağlı adet argümanını doğrular.
+func parseLimit(command string, args []string) (int, bool) {
+	switch command {
+	case "status", "redo":
+		return 0, len(args) == 0
+	case "up", "down":
+		if len(args) > 1 {
+			return 0, false
+		}
+		limit := 0
+		if command == "down" {
+			limit = 1
+		}
+		if len(args) == 1 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n <= 0 {
+				return 0, false
+			}
+			limit = n
+		}
+		return limit, true
+	default:
+		return 0, false
+	}
+}
+
+// parseSeedArgs seed komutunun seçeneklerini ve seeder adlarını okur.
+func parseSeedArgs(args []string) (seeders.Options, []string, bool) {
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	env := fs.String("env", string(seeders.DefaultEnv()), "seed ortamı (dev, test, prod)")
+	count := fs.Int("count", 0, "sahte veri üreten seeder'ların oluşturacağı kayıt sayısı")
+	fakerSeed := fs.Uint64("faker-seed", 0, "sahte veri tohumu; 0 ise her seferinde farklı veri")
+	if err := fs.Parse(args); err != nil {
+		return seeders.Options{}, nil, false
+	}
+
+	parsedEnv, err := seeders.ParseEnv(*env)
+	if err != nil || *count < 0 {
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		return seeders.Options{}, nil, false
+	}
+	return seeders.Options{Env: parsedEnv, Count: *count, FakerSeed: *fakerSeed}, fs.Args(), true
+}
+
+func report(n int, message string, err error) int {
+	if err != nil {
+		if n > 0 {
+			fmt.Fprintf(os.Stderr, "%d %s, ardından hata oluştu\n", n, message)
+		}
+		fmt.Fprintln(os.Stderr, "Hata:", err)
+		return exitError
+	}
+	fmt.Fprintf(os.Stderr, "%d %s\n", n, message)
+	return exitOK
+}
+
+func printStatus(ctx context.Context, m *migrator.Migrator) int {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Hata:", err)
+		return exitError
+	}
+
+	pending, missing := 0, 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SÜRÜM\tİSİM\tDURUM")
+	for _, s := range statuses {
+		state := "bekliyor"
+		switch {
+		case s.Missing:
+			state = "kodda yok (" + s.AppliedAt.Format(time.DateTime) + ")"
+			missing++
+		case s.AppliedAt != nil:
+			state = "uygulandı (" + s.AppliedAt.Format(time.DateTime) + ")"
+		default:
+			pending++
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", s.Version, s.Name, state)
+	}
+	w.Flush()
+
+	if missing > 0 {
+		fmt.Fprintf(os.Stderr, "%d uygulanmış migrasyonun kodu bulunamadı\n", missing)
+		return exitError
+	}
+	if pending > 0 {
+		fmt.Fprintf(os.Stderr, "%d bekleyen migrasyon var\n", pending)
+		return exitPending
+	}
+	return exitOK
+}
+
+func createMigration(dir, name string) int {
+	version := time.Now().UTC().Format("20060102150405")
+	base := filepath.Join(dir, version+"_"+name)
+
+	code := writeMigrationFiles([]migrationFile{
+		{base + ".up.sql", "-- " + name + " migrasyonu\n"},
+		{base + ".down.sql", "-- " + name + " migrasyonunu geri alır; boş bırakılırsa geri alma bir şey yapmaz.\n-- Geri alınamayacaksa bu dosyayı silin.\n"},
+	})
+	if code == exitOK {
+		fmt.Fprintln(os.Stderr, "Sürücüye özel SQL için dosya adına sürücüyü ekleyin (ör. "+version+"_"+name+".postgres.up.sql).")
+	}
+	return code
+}
+
+// diffSchema modellerle veritabanı arasındaki farkı çalışan sürücüye özel bir SQL
+// migrasyonu olarak yazar. Bekleyen migrasyonlar varsa fark onları da içereceği için
+// önce uygulanmaları istenir.
+// diffSchema modelleri migrasyonların kurduğu şemayla karşılaştırır. Fark ancak
+// veritabanı kayıt defterindeki migrasyonlarla kurulmuşsa yeni migrasyonun içeriğidir;
+// bu yüzden bekleyen ya da kodu bulunmayan migrasyon varsa fark alınmaz.
+func diffSchema(ctx context.Context, db *gorm.DB, m *migrator.Migrator, dir, name string, dryRun bool) int {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Hata:", err)
+		return exitError
+	}
+	pending, missing := 0, 0
+	for _, s := range statuses {
+		switch {
+		case s.Missing:
+			missing++
+		case s.AppliedAt == nil:
+			pending++
+		}
+	}
+	if missing > 0 {
+		fmt.Fprintf(os.Stderr, "%d uygulanmış migrasyonun kodu yok; şema kayıt defterinden bilinemediği için fark alınmadı\n", missing)
+		return exitError
+	}
+	if pending > 0 {
+		fmt.Fprintf(os.Stderr, "%d bekleyen migrasyon var; fark almadan önce 'up' çalıştırın\n", pending)
+		return exitPending
+	}
 
-	db := database_config.GetDB()
+	changes, err := database.SchemaDiff(ctx, db)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Hata:", err)
+		return exitError
+	}
+	if len(changes) == 0 {
+		fmt.Fprintln(os.Stderr, "Şema modellerle uyumlu, migrasyon oluşturulmadı")
+		return exitOK
+	}
+
+	var up, down strings.Builder
+	if err := migrator.WriteDiff(&up, &down, changes); err != nil {
+		fmt.Fprintln(os.Stderr, "Hata:", err)
+		return exitError
+	}
+
+	code := exitOK
+	if dryRun {
+		fmt.Print(up.String())
+	} else {
+		version := time.Now().UTC().Format("20060102150405")
+		base := filepath.Join(dir, version+"_"+name+"."+db.Dialector.Name())
+		code = writeMigrationFiles([]migrationFile{
+			{base + ".up.sql", up.String()},
+			{base + ".down.sql", down.String()},
+		})
+	}
+
+	destructive := 0
+	for _, c := range changes {
+		if c.Destructive {
+			destructive++
+		}
+	}
+	fmt.Fprintf(os.Stderr, "%d değişiklik bulundu", len(changes))
+	if destructive > 0 {
+		fmt.Fprintf(os.Stderr, ", %d tanesi YIKICI olarak işaretlendi ve yorum satırı bırakıldı", destructive)
+	}
+	fmt.Fprintln(os.Stderr)
+	if code != exitOK {
+		return code
+	}
+	fmt.Fprintf(os.Stderr, "Dosyalar yalnızca %s içindir; diğer sürücülerde migrasyon dosyası bulunmadığı için hata verir. "+
+		"Her sürücü için DB_DRIVER ile ayrıca diff alın ya da sürücüsüz .up.sql/.down.sql dosyası ekleyin\n", db.Dialector.Name())
+	return exitOK
+}
 
-	log_config.SLog.Info("Veritabanı başlatma işlemi çalıştırılıyor...")
-	database.Initialize(db, *migrateFlag, *seedFlag)
+type migrationFile struct{ path, content string }
 
-	log_config.SLog.Info("Veritabanı başlatma işlemi tamamlandı.")
+// writeMigrationFiles dosyalardan biri zaten varsa hiçbirini yazmaz.
+func writeMigrationFiles(files []migrationFile) int {
+	for _, f := range files {
+		if _, err := os.Stat(f.path); err == nil {
+			fmt.Fprintln(os.Stderr, "Dosya zaten var:", f.path)
+			return exitError
+		} else if !errors.Is(err, os.ErrNotExist) {
+			fmt.Fprintln(os.Stderr, "Hata:", err)
+			return exitError
+		}
+	}
+	for _, f := range files {
+		if err := os.WriteFile(f.path, []byte(f.content), 0o644); err != nil {
+			fmt.Fprintln(os.Stderr, "Dosya oluşturulamadı:", err)
+			return exitError
+		}
+		fmt.Println(f.path)
+	}
+	return exitOK
 }
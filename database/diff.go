@@ -0,0 +1,58 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"zatrano/database/migrations"
+	"zatrano/models"
+	"zatrano/pkg/fulltext"
+	"zatrano/pkg/migrator"
+	"zatrano/pkg/pgenum"
+
+	"gorm.io/gorm"
+)
+
+// SchemaDiff enum tiplerini ve migrations.Models içindeki modelleri veritabanıyla
+// karşılaştırıp gereken değişiklikleri döner; veritabanında bir şey değiştirmez.
+// Enum değişiklikleri, onları kullanan sütunlardan önce gelir.
+func SchemaDiff(ctx context.Context, db *gorm.DB) ([]migrator.Change, error) {
+	db = db.WithContext(ctx)
+
+	var changes []migrator.Change
+	for _, e := range models.Enums() {
+		statements, err := migrator.Capture(db, func(tx *gorm.DB) error {
+			return pgenum.Sync(tx, e)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s enum tipi karşılaştırılamadı: %w", e.EnumName(), err)
+		}
+		if len(statements) > 0 {
+			changes = append(changes, migrator.Change{
+				Table:   e.EnumName(),
+				Summary: e.EnumName() + " enum tipi koddaki değerlerle eşitlenecek",
+				Up:      statements,
+			})
+		}
+	}
+
+	var targets []migrator.Target
+	for _, model := range migrations.Models() {
+		target := migrator.Target{Model: model}
+		if _, ok := model.(models.FullTextSearchable); ok && db.Dialector.Name() == "postgres" {
+			stmt := &gorm.Statement{DB: db}
+			if err := stmt.Parse(model); err != nil {
+				return nil, err
+			}
+			target.ManagedColumns = []string{fulltext.VectorColumn}
+			target.ManagedIndexes = []string{migrations.SearchVectorIndexName(stmt.Schema.Table)}
+		}
+		targets = append(targets, target)
+	}
+
+	tableChanges, err := migrator.Diff(ctx, db, targets...)
+	if err != nil {
+		return nil, err
+	}
+	return append(changes, tableChanges...), nil
+}
@@ -0,0 +1,142 @@
+package factories
+
+import (
+	"context"
+	"time"
+
+	"zatrano/models"
+	"zatrano/pkg/faker"
+	"zatrano/pkg/txmanager"
+	"zatrano/repositories"
+
+	"gorm.io/gorm"
+)
+
+// DefaultUserPassword factory ile oluşturulan kullanıcıların ortak şifresidir.
+const DefaultUserPassword = "Zatrano123!"
+
+const userBatchSize = 100
+
+// UserFactory sahte kullanıcılar üretir. Şifre bir kez hash'lenip tüm kullanıcılarda
+// kullanılır; bcrypt'in kullanıcı başına maliyeti yüzlerce kayıtta saniyeler tutar.
+type UserFactory struct {
+	faker  *faker.Faker
+	hashed string
+	states []func(*models.User)
+}
+
+func NewUserFactory(f *faker.Faker) (*UserFactory, error) {
+	var user models.User
+	if err := user.SetPassword(DefaultUserPassword); err != nil {
+		return nil, err
+	}
+	return &UserFactory{faker: f, hashed: user.Password}, nil
+}
+
+// With üretilen her kullanıcıya uygulanacak bir değişiklik ekler ve yeni bir factory döner:
+//
+//	factory.With(func(u *models.User) { u.TenantID = tenant.ID }).Create(ctx, db, 10)
+func (uf *UserFactory) With(state func(*models.User)) *UserFactory {
+	clone := *uf
+	clone.states = append(uf.states[:len(uf.states):len(uf.states)], state)
+	return &clone
+}
+
+// Make kaydetmeden tek bir kullanıcı üretir.
+func (uf *UserFactory) Make() models.User {
+	f := uf.faker
+	firstName, lastName := f.FirstName(), f.LastName()
+	account := f.Username(firstName, lastName)
+	createdAt := f.Time(time.Now().AddDate(-1, 0, 0), time.Now())
+
+	user := models.User{
+		Name:       firstName + " " + lastName,
+		Account:    account,
+		AccountKey: models.AccountKey(account),
+		Password:   uf.hashed,
+		Status:     f.Chance(0.9),
+		Type:       models.Panel,
+	}
+	user.CreatedAt = createdAt
+	if f.Chance(0.1) {
+		user.Type = models.Dashboard
+	}
+	if f.Chance(0.7) {
+		email := f.Email(account)
+		user.Email = &email
+		if f.Chance(0.6) {
+			verifiedAt := f.Time(createdAt, time.Now())
+			user.EmailVerifiedAt = &verifiedAt
+		}
+	}
+
+	for _, state := range uf.states {
+		state(&user)
+	}
+	return user
+}
+
+func (uf *UserFactory) MakeMany(n int) []models.User {
+	users := make([]models.User, n)
+	for i := range users {
+		users[i] = uf.Make()
+	}
+	return users
+}
+
+// Create n kullanıcı üretip kaydeder ve hiyerarşiye ekler. Hesap adı mevcut
+// kullanıcılarla çakışanlar yeniden üretilir. ctx içinde oluşturan kullanıcının
+// kimliği (user_id) bulunmalıdır.
+func (uf *UserFactory) Create(ctx context.Context, db *gorm.DB, n int) ([]models.User, error) {
+	users := uf.MakeMany(n)
+	if err := uf.ensureUniqueAccounts(ctx, db, users); err != nil {
+		return nil, err
+	}
+
+	tx := db.WithContext(ctx)
+	if err := tx.CreateInBatches(&users, userBatchSize).Error; err != nil {
+		return nil, err
+	}
+
+	hierarchy := repositories.NewUserHierarchyRepository()
+	txCtx := txmanager.WithTx(ctx, tx)
+	for _, user := range users {
+		if err := hierarchy.AttachUser(txCtx, user.ID, user.ParentID); err != nil {
+			return nil, err
+		}
+	}
+	return users, nil
+}
+
+func (uf *UserFactory) ensureUniqueAccounts(ctx context.Context, db *gorm.DB, users []models.User) error {
+	seen := make(map[string]bool, len(users))
+	for {
+		keys := make([]string, 0, len(users))
+		for _, user := range users {
+			keys = append(keys, user.AccountKey)
+		}
+		var taken []string
+		err := db.WithContext(ctx).Unscoped().Model(&models.User{}).
+			Where("account_key IN ?", keys).Pluck("account_key", &taken).Error
+		if err != nil {
+			return err
+		}
+		for _, key := range taken {
+			seen[key] = true
+		}
+
+		retry := false
+		batch := make(map[string]bool, len(users))
+		for i := range users {
+			if seen[users[i].AccountKey] || batch[users[i].AccountKey] {
+				users[i] = uf.Make()
+				retry = true
+				continue
+			}
+			batch[users[i].AccountKey] = true
+		}
+		if !retry {
+			return nil
+		}
+	}
+}
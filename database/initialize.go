@@ -1,10 +1,15 @@
 package database
 
 import (
+	"context"
+	"fmt"
+
 	"zatrano/configs/logconfig"
 	"zatrano/database/migrations"
 	"zatrano/database/seeders"
 	"zatrano/models"
+	"zatrano/pkg/migrator"
+	"zatrano/pkg/pgenum"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -16,24 +21,11 @@ func Initialize(db *gorm.DB, migrate bool, seed bool) {
 		return
 	}
 
-	tx := db.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-			logconfig.Log.Fatal("Veritabanı başlatma işlemi başarısız oldu, geri alındı (panic)", zap.Any("panic_info", r))
-		}
-		if tx.Error != nil && tx.Error != gorm.ErrInvalidTransaction {
-			logconfig.SLog.Warn("Başlatma sırasında hata oluştuğu için işlem geri alınıyor.")
-			tx.Rollback()
-		}
-	}()
-
 	logconfig.SLog.Info("Veritabanı başlatma işlemi başlıyor...")
 
 	if migrate {
 		logconfig.SLog.Info("Migrasyonlar çalıştırılıyor...")
-		if err := RunMigrationsInOrder(tx); err != nil {
-			tx.Rollback()
+		if err := RunMigrations(context.Background(), db); err != nil {
 			logconfig.Log.Fatal("Migrasyon başarısız oldu", zap.Error(err))
 		}
 		logconfig.SLog.Info("Migrasyonlar tamamlandı.")
@@ -43,8 +35,7 @@ func Initialize(db *gorm.DB, migrate bool, seed bool) {
 
 	if seed {
 		logconfig.SLog.Info("Seeder'lar çalıştırılıyor...")
-		if err := CheckAndRunSeeders(tx); err != nil {
-			tx.Rollback()
+		if err := RunSeeders(context.Background(), db, seeders.Options{Env: seeders.DefaultEnv()}); err != nil {
 			logconfig.Log.Fatal("Seeding başarısız oldu", zap.Error(err))
 		}
 		logconfig.SLog.Info("Seeder'lar tamamlandı.")
@@ -52,52 +43,66 @@ func Initialize(db *gorm.DB, migrate bool, seed bool) {
 		logconfig.SLog.Info("Seed bayrağı belirtilmedi, seeder adımı atlanıyor.")
 	}
 
-	logconfig.SLog.Info("İşlem commit ediliyor...")
-	if err := tx.Commit().Error; err != nil {
-		logconfig.Log.Fatal("Commit başarısız oldu", zap.Error(err))
-	}
-
 	logconfig.SLog.Info("Veritabanı başlatma işlemi başarıyla tamamlandı")
 }
 
-func RunMigrationsInOrder(db *gorm.DB) error {
-	logconfig.SLog.Info(" -> User migrasyonları çalıştırılıyor...")
-	if err := migrations.MigrateUsersTable(db); err != nil {
-		logconfig.Log.Error("Users tablosu migrasyonu başarısız oldu", zap.Error(err))
-		return err
+// NewMigrator kayıtlı tüm Go ve SQL migrasyonlarıyla bir migrator oluşturur.
+func NewMigrator(db *gorm.DB) (*migrator.Migrator, error) {
+	all, err := migrations.All()
+	if err != nil {
+		return nil, err
 	}
-	logconfig.SLog.Info(" -> User migrasyonları tamamlandı.")
+	return migrator.New(db, all)
+}
 
-	logconfig.SLog.Info("Tüm migrasyonlar başarıyla çalıştırıldı.")
+// RunMigrations bekleyen tüm migrasyonları sürüm sırasıyla, her birini kendi
+// işleminde uygular.
+func RunMigrations(ctx context.Context, db *gorm.DB) error {
+	m, err := NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	if err := SyncEnums(ctx, m); err != nil {
+		return err
+	}
+	applied, err := m.Up(ctx, 0)
+	if err != nil {
+		return err
+	}
+	if applied == 0 {
+		logconfig.SLog.Info(" -> Bekleyen migrasyon yok, şema güncel.")
+	} else {
+		logconfig.SLog.Infof(" -> %d migrasyon uygulandı.", applied)
+	}
 	return nil
 }
 
-func CheckAndRunSeeders(db *gorm.DB) error {
-	systemUser := seeders.GetSystemUserConfig()
-	var existingUser models.User
-	result := db.Where("account = ? AND type = ?", systemUser.Account, models.Dashboard).First(&existingUser)
-
-	if result.Error != nil {
-		if result.Error == gorm.ErrRecordNotFound {
-			logconfig.SLog.Info("Sistem kullanıcısı oluşturuluyor: %s (%s)...", systemUser.Name, systemUser.Account)
-			if err := seeders.SeedSystemUser(db); err != nil {
-				logconfig.Log.Error("Sistem kullanıcısı seed edilemedi", zap.Error(err))
-				return err
-			}
-			logconfig.SLog.Info(" -> Sistem kullanıcısı oluşturuldu.")
-		} else {
-			logconfig.Log.Error("Sistem kullanıcısı kontrol edilirken hata", zap.Error(result.Error))
-			return result.Error
-		}
-	} else {
-		logconfig.SLog.Info("Sistem kullanıcısı '%s' (%s) zaten mevcut, oluşturma adımı atlanıyor.",
-			existingUser.Name, existingUser.Account)
-		logconfig.SLog.Info("Mevcut sistem kullanıcısı '%s' için güncelleme kontrolü yapılıyor...", existingUser.Account)
-		if err := seeders.SeedSystemUser(db); err != nil {
-			logconfig.Log.Error("Mevcut sistem kullanıcısı güncellenirken/kontrol edilirken hata", zap.Error(err))
-			return err
+// SyncEnums models.Enums içindeki enum tiplerini migrasyon kilidi altında koddaki
+// değerlerle eşitler; yalnızca PostgreSQL'de iş yapar. Yeni değerlerin kendi
+// işlemlerinde kalıcı olması için migrasyonlardan önce çalıştırılır.
+func SyncEnums(ctx context.Context, m *migrator.Migrator) error {
+	for _, e := range models.Enums() {
+		err := m.WithinLock(ctx, func(tx *gorm.DB) error {
+			return pgenum.Sync(tx, e)
+		})
+		if err != nil {
+			return fmt.Errorf("%s enum tipi eşitlenemedi: %w", e.EnumName(), err)
 		}
+	}
+	return nil
+}
 
+// RunSeeders ad verilmezse ortamdaki tüm seeder'ları, verilirse yalnızca onları ve
+// bağımlılıklarını çalıştırır. Her seeder kendi işleminde çalışır.
+func RunSeeders(ctx context.Context, db *gorm.DB, opts seeders.Options, names ...string) error {
+	runner, err := seeders.NewRunner(db, seeders.All())
+	if err != nil {
+		return err
+	}
+	ran, err := runner.Run(ctx, opts, names...)
+	if err != nil {
+		return err
 	}
+	logconfig.SLog.Infof(" -> %d seeder çalıştırıldı (ortam: %s).", ran, opts.Env)
 	return nil
 }
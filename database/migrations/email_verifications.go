@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"errors"
+	"time"
+	"zatrano/configs/logconfig"
+
+	"gorm.io/gorm"
+)
+
+type emailVerificationsTable struct {
+	Base        baseColumns `gorm:"embedded"`
+	UserID      uint        `gorm:"not null;index"`
+	Email       string      `gorm:"size:255;not null"`
+	TokenHash   string      `gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt   time.Time   `gorm:"not null"`
+	ConfirmedAt *time.Time
+}
+
+func (emailVerificationsTable) TableName() string {
+	return "email_verifications"
+}
+
+func MigrateEmailVerificationsTable(db *gorm.DB) error {
+	logconfig.SLog.Info("EmailVerification tablosu migrate ediliyor...")
+	if err := createTable(db, &emailVerificationsTable{}); err != nil {
+		return errors.New("EmailVerification tablosu migrate edilemedi: " + err.Error())
+	}
+	logconfig.SLog.Info("EmailVerification tablosu migrate işlemi tamamlandı.")
+	return nil
+}
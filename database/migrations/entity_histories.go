@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"errors"
+	"time"
+	"zatrano/configs/logconfig"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+type entityHistoriesTable struct {
+	ID         uint           `gorm:"primarykey"`
+	EntityType string         `gorm:"size:100;not null;index:idx_entity_histories_entity,priority:1"`
+	EntityID   uint           `gorm:"not null;index:idx_entity_histories_entity,priority:2"`
+	Action     string         `gorm:"size:20;not null"`
+	ActorID    *uint          `gorm:"index"`
+	Changes    historyChanges `gorm:"not null"`
+	CreatedAt  time.Time      `gorm:"index"`
+}
+
+func (entityHistoriesTable) TableName() string {
+	return "entity_histories"
+}
+
+type historyChanges string
+
+func (historyChanges) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	switch db.Dialector.Name() {
+	case "postgres":
+		return "jsonb"
+	case "mysql":
+		return "json"
+	default:
+		return "text"
+	}
+}
+
+func MigrateEntityHistoriesTable(db *gorm.DB) error {
+	logconfig.SLog.Info("EntityHistory tablosu migrate ediliyor...")
+	if err := createTable(db, &entityHistoriesTable{}); err != nil {
+		return errors.New("EntityHistory tablosu migrate edilemedi: " + err.Error())
+	}
+	logconfig.SLog.Info("EntityHistory tablosu migrate işlemi tamamlandı.")
+	return nil
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"zatrano/configs/logconfig"
+
+	"gorm.io/gorm"
+)
+
+// searchExtensions Türkçe arama için kullanılan isteğe bağlı PostgreSQL eklentileridir.
+// Kurulamazlarsa (ör. yetki yoksa) arama translate() ile ve bulanık eşleşme olmadan çalışır.
+var searchExtensions = []string{"unaccent", "pg_trgm"}
+
+func MigrateSearchExtensions(db *gorm.DB) error {
+	if db.Dialector.Name() != "postgres" {
+		return nil
+	}
+
+	for _, ext := range searchExtensions {
+		// Başarısız CREATE EXTENSION işlemin tamamını iptal etmesin diye savepoint kullanılır.
+		savepoint := "ext_" + ext
+		if err := db.SavePoint(savepoint).Error; err != nil {
+			return err
+		}
+		if err := db.Exec("CREATE EXTENSION IF NOT EXISTS " + ext).Error; err != nil {
+			logconfig.SLog.Warnw("Arama eklentisi kurulamadı, eklentisiz devam ediliyor", "extension", ext, "error", err)
+			if err := db.RollbackTo(savepoint).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
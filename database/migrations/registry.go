@@ -0,0 +1,139 @@
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+	"time"
+
+	"zatrano/models"
+	"zatrano/pkg/migrator"
+
+	"gorm.io/gorm"
+)
+
+//go:embed sql/*.sql
+var sqlFiles embed.FS
+
+// goMigrations Go ile yazılmış migrasyonlardır; SQL migrasyonları sql/ dizinindedir.
+// Uygulanmış bir migrasyonun sürümü ve içeriği değiştirilmez, değişiklik için yeni
+// migrasyon eklenir. Migrasyonlar canlı modeller yerine kendi dosyalarındaki anlık
+// görüntü yapılarını kullanır; model sonradan değiştiğinde eski migrasyonların
+// ürettiği şema değişmez.
+var goMigrations = []migrator.Migration{
+	{Version: "20261018100000", Name: "create_search_extensions", Up: MigrateSearchExtensions, Down: keepExtensions},
+	{Version: "20261018100100", Name: "create_tenants", Up: MigrateTenantsTable, Down: dropTable(&tenantsTable{})},
+	{Version: "20261018100300", Name: "create_users", Up: MigrateUsersTable, Down: dropTable(&usersTable{})},
+	{Version: "20261018100400", Name: "create_user_hierarchies", Up: MigrateUserHierarchiesTable, Down: dropTable(&userHierarchiesTable{})},
+	{Version: "20261018100500", Name: "create_user_invitations", Up: MigrateUserInvitationsTable, Down: dropTable(&userInvitationsTable{})},
+	{Version: "20261018100600", Name: "create_email_verifications", Up: MigrateEmailVerificationsTable, Down: dropTable(&emailVerificationsTable{})},
+	{Version: "20261018100700", Name: "create_entity_histories", Up: MigrateEntityHistoriesTable, Down: dropTable(&entityHistoriesTable{})},
+	{Version: "20261018190000", Name: "create_seeder_runs", Up: MigrateSeederRunsTable, Down: dropTable(&seederRunsTable{})},
+	{Version: "20261018193000", Name: "add_users_tenant", Up: AddUsersTenant, Down: DropUsersTenant},
+	{Version: "20261018193100", Name: "add_users_parent_id", Up: AddUsersParentID, Down: DropUsersParentID},
+	{Version: "20261018193200", Name: "add_users_email_and_account_key", Up: AddUsersEmailAndAccountKey, Down: DropUsersEmailAndAccountKey},
+	{Version: "20261018193300", Name: "add_users_search_vector", Up: AddUsersSearchVector, Down: DropUsersSearchVector},
+	{Version: "20261018193400", Name: "add_users_version", Up: AddUsersVersion, Down: DropUsersVersion},
+}
+
+// baseColumns BaseModel'in tablolar oluşturulduğu andaki sütunlarıdır.
+type baseColumns struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+	CreatedBy uint
+	UpdatedBy uint
+	DeletedBy *uint
+}
+
+// Models şeması migrasyonlarla yönetilen modellerdir; diff komutu bunları veritabanıyla
+// karşılaştırır. Yeni bir model eklendiğinde buraya da eklenmelidir.
+func Models() []interface{} {
+	return []interface{}{
+		&models.Tenant{},
+		&models.User{},
+		&models.UserHierarchy{},
+		&models.UserInvitation{},
+		&models.EmailVerification{},
+		&models.EntityHistory{},
+		&models.SeederRun{},
+	}
+}
+
+// All Go ve SQL migrasyonlarının tamamını döner; sıralama migrator.New'dedir.
+func All() ([]migrator.Migration, error) {
+	dir, err := fs.Sub(sqlFiles, "sql")
+	if err != nil {
+		return nil, err
+	}
+	sqlMigrations, err := migrator.LoadSQL(dir)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]migrator.Migration(nil), goMigrations...), sqlMigrations...), nil
+}
+
+// keepExtensions eklentileri kaldırmaz; veritabanındaki başka nesneler de kullanıyor olabilir.
+func keepExtensions(tx *gorm.DB) error {
+	return nil
+}
+
+func dropTable(model interface{}) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(model)
+	}
+}
+
+// createTable tablo yoksa anlık görüntüden oluşturur; kayıt defterinden önce
+// AutoMigrate ile kurulmuş veritabanlarında tablo zaten vardır.
+func createTable(tx *gorm.DB, snapshot interface{}) error {
+	if tx.Migrator().HasTable(snapshot) {
+		return nil
+	}
+	return tx.Migrator().CreateTable(snapshot)
+}
+
+// addColumns anlık görüntüdeki sütunlardan tabloda olmayanları ekler.
+func addColumns(tx *gorm.DB, snapshot interface{}, columns ...string) error {
+	for _, column := range columns {
+		if tx.Migrator().HasColumn(snapshot, column) {
+			continue
+		}
+		if err := tx.Migrator().AddColumn(snapshot, column); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dropColumns(tx *gorm.DB, snapshot interface{}, columns ...string) error {
+	for _, column := range columns {
+		if !tx.Migrator().HasColumn(snapshot, column) {
+			continue
+		}
+		if err := tx.Migrator().DropColumn(snapshot, column); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createIndexes anlık görüntülerdeki indekslerden eksik olanları oluşturur. SQLite'ta
+// sütun değiştirme ve silme tabloyu yeniden kurduğundan indeksler bununla geri getirilir.
+func createIndexes(tx *gorm.DB, snapshots ...interface{}) error {
+	for _, snapshot := range snapshots {
+		stmt := &gorm.Statement{DB: tx}
+		if err := stmt.Parse(snapshot); err != nil {
+			return err
+		}
+		for _, index := range stmt.Schema.ParseIndexes() {
+			if tx.Migrator().HasIndex(snapshot, index.Name) {
+				continue
+			}
+			if err := tx.Migrator().CreateIndex(snapshot, index.Name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package migrations
+
+import (
+	"errors"
+
+	"zatrano/configs/logconfig"
+	"zatrano/models"
+	"zatrano/pkg/fulltext"
+
+	"gorm.io/gorm"
+)
+
+// SearchVectorIndexName tablonun search_vector sütunundaki GIN indeksinin adıdır.
+func SearchVectorIndexName(table string) string {
+	return "idx_" + table + "_" + fulltext.VectorColumn
+}
+
+// MigrateSearchVector modelin arama alanlarından search_vector üretilmiş sütununu ve
+// GIN indeksini oluşturur. Alan tanımının özeti sütun yorumunda tutulur; tanım
+// değiştiğinde sütun (ve indeksi) yeniden oluşturulur. PostgreSQL dışında atlanır.
+func MigrateSearchVector(db *gorm.DB, model models.FullTextSearchable) error {
+	if db.Dialector.Name() != "postgres" {
+		return nil
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return errors.New("arama sütunu için model çözümlenemedi: " + err.Error())
+	}
+	table := stmt.Schema.Table
+	fields := model.SearchFields()
+	signature := fulltext.Signature(fields)
+	indexName := SearchVectorIndexName(table)
+
+	var current *string
+	err := db.Raw(`SELECT col_description(a.attrelid, a.attnum) FROM pg_attribute a
+		WHERE a.attrelid = ?::regclass AND a.attname = ? AND NOT a.attisdropped`, table, fulltext.VectorColumn).Scan(&current).Error
+	if err != nil {
+		return errors.New(table + "." + fulltext.VectorColumn + " kontrol edilemedi: " + err.Error())
+	}
+
+	if current == nil || *current != signature {
+		logconfig.SLog.Infof("%s.%s arama sütunu oluşturuluyor...", table, fulltext.VectorColumn)
+		statements := []string{
+			"ALTER TABLE " + table + " DROP COLUMN IF EXISTS " + fulltext.VectorColumn,
+			"ALTER TABLE " + table + " ADD COLUMN " + fulltext.VectorColumn + " tsvector GENERATED ALWAYS AS (" + fulltext.VectorExpression(fields) + ") STORED",
+			"COMMENT ON COLUMN " + table + "." + fulltext.VectorColumn + " IS '" + signature + "'",
+		}
+		for _, sql := range statements {
+			if err := db.Exec(sql).Error; err != nil {
+				return errors.New(table + "." + fulltext.VectorColumn + " oluşturulamadı: " + err.Error())
+			}
+		}
+	}
+
+	if err := db.Exec("CREATE INDEX IF NOT EXISTS " + indexName + " ON " + table + " USING GIN (" + fulltext.VectorColumn + ")").Error; err != nil {
+		return errors.New(indexName + " indeksi oluşturulamadı: " + err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"errors"
+	"time"
+	"zatrano/configs/logconfig"
+
+	"gorm.io/gorm"
+)
+
+type seederRunsTable struct {
+	Name  string    `gorm:"primaryKey;size:100"`
+	Env   string    `gorm:"size:10;not null"`
+	Count int       `gorm:"not null;default:0"`
+	RanAt time.Time `gorm:"not null"`
+}
+
+func (seederRunsTable) TableName() string {
+	return "seeder_runs"
+}
+
+func MigrateSeederRunsTable(db *gorm.DB) error {
+	logconfig.SLog.Info("SeederRun tablosu migrate ediliyor...")
+	if err := createTable(db, &seederRunsTable{}); err != nil {
+		return errors.New("SeederRun tablosu migrate edilemedi: " + err.Error())
+	}
+	logconfig.SLog.Info("SeederRun tablosu migrate işlemi tamamlandı.")
+	return nil
+}
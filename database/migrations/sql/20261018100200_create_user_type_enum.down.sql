@@ -0,0 +1 @@
+-- user_type enum tipi yalnızca PostgreSQL'de vardır.
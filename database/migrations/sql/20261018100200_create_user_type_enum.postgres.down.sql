@@ -0,0 +1 @@
+DROP TYPE IF EXISTS user_type;
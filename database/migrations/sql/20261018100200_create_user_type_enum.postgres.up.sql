@@ -0,0 +1,7 @@
+DO $$
+BEGIN
+    CREATE TYPE user_type AS ENUM ('dashboard', 'panel');
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END
+$$;
@@ -0,0 +1,2 @@
+-- user_type enum tipi yalnızca PostgreSQL'de vardır; diğer sürücülerde users.type
+-- sütunu varchar olarak oluşturulur.
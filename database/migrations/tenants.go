@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"errors"
+	"zatrano/configs/logconfig"
+
+	"gorm.io/gorm"
+)
+
+type tenantsTable struct {
+	Base   baseColumns `gorm:"embedded"`
+	Name   string      `gorm:"size:100;not null"`
+	Slug   string      `gorm:"size:63;unique;not null"`
+	Status bool        `gorm:"default:true;index"`
+}
+
+func (tenantsTable) TableName() string {
+	return "tenants"
+}
+
+func MigrateTenantsTable(db *gorm.DB) error {
+	logconfig.SLog.Info("Tenant tablosu migrate ediliyor...")
+	if err := createTable(db, &tenantsTable{}); err != nil {
+		return errors.New("Tenant tablosu migrate edilemedi: " + err.Error())
+	}
+
+	logconfig.SLog.Info("Tenant tablosu migrate işlemi tamamlandı.")
+	return nil
+}
@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"errors"
+	"zatrano/configs/logconfig"
+
+	"gorm.io/gorm"
+)
+
+type userHierarchiesTable struct {
+	AncestorID   uint `gorm:"primaryKey;autoIncrement:false"`
+	DescendantID uint `gorm:"primaryKey;autoIncrement:false;index"`
+	Depth        int  `gorm:"not null;index"`
+}
+
+func (userHierarchiesTable) TableName() string {
+	return "user_hierarchies"
+}
+
+func MigrateUserHierarchiesTable(db *gorm.DB) error {
+	logconfig.SLog.Info("UserHierarchy tablosu migrate ediliyor...")
+	if err := createTable(db, &userHierarchiesTable{}); err != nil {
+		return errors.New("UserHierarchy tablosu migrate edilemedi: " + err.Error())
+	}
+
+	logconfig.SLog.Info("Hiyerarşide kaydı olmayan kullanıcılar için kök kayıtları ekleniyor...")
+	err := db.Exec(`INSERT INTO user_hierarchies (ancestor_id, descendant_id, depth)
+		SELECT u.id, u.id, 0 FROM users u
+		WHERE NOT EXISTS (SELECT 1 FROM user_hierarchies h WHERE h.ancestor_id = u.id AND h.descendant_id = u.id)`).Error
+	if err != nil {
+		return errors.New("Hiyerarşi kök kayıtları eklenemedi: " + err.Error())
+	}
+
+	logconfig.SLog.Info("UserHierarchy tablosu migrate işlemi tamamlandı.")
+	return nil
+}
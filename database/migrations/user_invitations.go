@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"errors"
+	"time"
+	"zatrano/configs/logconfig"
+
+	"gorm.io/gorm"
+)
+
+type userInvitationsTable struct {
+	Base       baseColumns `gorm:"embedded"`
+	UserID     uint        `gorm:"not null;index"`
+	User       *userRef    `gorm:"foreignKey:UserID"`
+	Email      string      `gorm:"size:255;not null"`
+	TokenHash  string      `gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt  time.Time   `gorm:"not null"`
+	AcceptedAt *time.Time
+	RevokedAt  *time.Time
+	SentCount  int `gorm:"not null;default:0"`
+	LastSentAt *time.Time
+}
+
+func (userInvitationsTable) TableName() string {
+	return "user_invitations"
+}
+
+// userRef yabancı anahtar kısıtı için users tablosunun birincil anahtarıdır.
+type userRef struct {
+	ID uint `gorm:"primarykey"`
+}
+
+func (userRef) TableName() string {
+	return "users"
+}
+
+func MigrateUserInvitationsTable(db *gorm.DB) error {
+	logconfig.SLog.Info("UserInvitation tablosu migrate ediliyor...")
+	if err := createTable(db, &userInvitationsTable{}); err != nil {
+		return errors.New("UserInvitation tablosu migrate edilemedi: " + err.Error())
+	}
+	logconfig.SLog.Info("UserInvitation tablosu migrate işlemi tamamlandı.")
+	return nil
+}
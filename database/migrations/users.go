@@ -2,39 +2,274 @@ package migrations
 
 import (
 	"errors"
+	"strings"
+	"time"
 	"zatrano/configs/logconfig"
 	"zatrano/models"
+	"zatrano/pkg/fulltext"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
 )
 
+// usersTable kullanıcı tablosunun ilk halidir; sonraki sütunlar ayrı migrasyonlarla eklenir.
+type usersTable struct {
+	Base     baseColumns `gorm:"embedded"`
+	Name     string      `gorm:"size:100;not null;index"`
+	Account  string      `gorm:"size:100;unique;not null"`
+	Password string      `gorm:"size:255;not null"`
+	Status   bool        `gorm:"default:true;index"`
+	Type     userType    `gorm:"type:user_type;not null;default:'panel';index"`
+}
+
+type userType string
+
+func (userType) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	if db.Dialector.Name() == "postgres" {
+		return "user_type"
+	}
+	return "varchar(10)"
+}
+
+type userTenantColumns struct {
+	TenantID     uint `gorm:"not null;default:0;index"`
+	IsSuperAdmin bool `gorm:"default:false"`
+}
+
+type userParentColumn struct {
+	ID       uint               `gorm:"primarykey"`
+	ParentID *uint              `gorm:"index"`
+	Children []userParentColumn `gorm:"foreignKey:ParentID"`
+}
+
+type userEmailColumns struct {
+	AccountKey      string  `gorm:"size:100;not null;uniqueIndex"`
+	Email           *string `gorm:"size:255;uniqueIndex"`
+	EmailVerifiedAt *time.Time
+}
+
+type userSearchColumns struct{}
+
+func (userSearchColumns) SearchFields() []fulltext.Field {
+	return []fulltext.Field{
+		{Column: "name", Weight: "A"},
+		{Column: "account", Weight: "A"},
+		{Column: "email", Weight: "B"},
+	}
+}
+
+type userVersionColumn struct {
+	Version uint `gorm:"not null;default:1"`
+}
+
+func (usersTable) TableName() string {
+	return "users"
+}
+
+func (userTenantColumns) TableName() string {
+	return "users"
+}
+
+func (userParentColumn) TableName() string {
+	return "users"
+}
+
+func (userEmailColumns) TableName() string {
+	return "users"
+}
+
+func (userSearchColumns) TableName() string {
+	return "users"
+}
+
+func (userVersionColumn) TableName() string {
+	return "users"
+}
+
 func MigrateUsersTable(db *gorm.DB) error {
-	logconfig.SLog.Info("User tablosu için enum tipi kontrol ediliyor...")
+	logconfig.SLog.Info("User tablosu migrate ediliyor...")
+	if err := createTable(db, &usersTable{}); err != nil {
+		return errors.New("User tablosu migrate edilemedi: " + err.Error())
+	}
 
-	dropEnumQuery := `DROP TYPE IF EXISTS user_type;`
-	rawDB, err := db.DB()
-	if err != nil {
-		return errors.New("DB instance alınamadı: " + err.Error())
+	logconfig.SLog.Info("User tablosu migrate işlemi tamamlandı.")
+	return nil
+}
+
+func AddUsersTenant(db *gorm.DB) error {
+	logconfig.SLog.Info("users tablosuna tenant sütunları ekleniyor...")
+	if err := addColumns(db, &userTenantColumns{}, "tenant_id", "is_super_admin"); err != nil {
+		return errors.New("tenant sütunları eklenemedi: " + err.Error())
+	}
+	return restoreUserIndexes(db, &userTenantColumns{})
+}
+
+func DropUsersTenant(db *gorm.DB) error {
+	if err := dropColumns(db, &userTenantColumns{}, "tenant_id", "is_super_admin"); err != nil {
+		return errors.New("tenant sütunları kaldırılamadı: " + err.Error())
+	}
+	return restoreUserIndexes(db)
+}
+
+func AddUsersParentID(db *gorm.DB) error {
+	logconfig.SLog.Info("users.parent_id sütunu ekleniyor...")
+	if err := addColumns(db, &userParentColumn{}, "parent_id"); err != nil {
+		return errors.New("parent_id sütunu eklenemedi: " + err.Error())
+	}
+	if !db.Migrator().HasConstraint(&userParentColumn{}, "Children") {
+		if err := db.Migrator().CreateConstraint(&userParentColumn{}, "Children"); err != nil {
+			return errors.New("parent_id yabancı anahtarı oluşturulamadı: " + err.Error())
+		}
+	}
+	return restoreUserIndexes(db, &userTenantColumns{}, &userParentColumn{})
+}
+
+func DropUsersParentID(db *gorm.DB) error {
+	if db.Migrator().HasConstraint(&userParentColumn{}, "Children") {
+		if err := db.Migrator().DropConstraint(&userParentColumn{}, "Children"); err != nil {
+			return errors.New("parent_id yabancı anahtarı kaldırılamadı: " + err.Error())
+		}
+	}
+	if err := dropColumns(db, &userParentColumn{}, "parent_id"); err != nil {
+		return errors.New("parent_id sütunu kaldırılamadı: " + err.Error())
+	}
+	return restoreUserIndexes(db, &userTenantColumns{})
+}
+
+// AddUsersEmailAndAccountKey e-posta sütunlarını ve hesap adının katlanmış halini
+// tutan account_key sütununu ekler; account üzerindeki büyük/küçük harf duyarlı
+// benzersizlik kısıtının yerini account_key'in benzersiz indeksi alır.
+func AddUsersEmailAndAccountKey(db *gorm.DB) error {
+	if err := prepareAccountKeys(db); err != nil {
+		return err
+	}
+
+	logconfig.SLog.Info("users tablosuna e-posta sütunları ekleniyor...")
+	if err := addColumns(db, &userEmailColumns{}, "email", "email_verified_at"); err != nil {
+		return errors.New("e-posta sütunları eklenemedi: " + err.Error())
+	}
+	return restoreUserIndexes(db, &userTenantColumns{}, &userParentColumn{}, &userEmailColumns{})
+}
+
+func DropUsersEmailAndAccountKey(db *gorm.DB) error {
+	if err := dropColumns(db, &userEmailColumns{}, "account_key", "email", "email_verified_at"); err != nil {
+		return errors.New("e-posta sütunları kaldırılamadı: " + err.Error())
+	}
+	if !db.Migrator().HasConstraint(&usersTable{}, "uni_users_account") {
+		if err := db.Migrator().CreateConstraint(&usersTable{}, "uni_users_account"); err != nil {
+			return errors.New("uni_users_account kısıtı oluşturulamadı: " + err.Error())
+		}
+	}
+	return restoreUserIndexes(db, &userTenantColumns{}, &userParentColumn{})
+}
+
+// prepareAccountKeys account_key sütununu boş değere izin verecek şekilde ekleyip
+// mevcut kullanıcılar için doldurur, ardından NOT NULL yapar ve account üzerindeki
+// eski benzersizlik kısıtını kaldırır. Katlanmış halleri çakışan hesaplar varsa
+// benzersiz indeks oluşturulamayacağı için migrasyon bu hesapları listeleyerek durur.
+func prepareAccountKeys(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasColumn(&userEmailColumns{}, "account_key") {
+		logconfig.SLog.Info("users.account_key sütunu ekleniyor...")
+		if err := db.Exec("ALTER TABLE users ADD COLUMN account_key varchar(100)").Error; err != nil {
+			return errors.New("account_key sütunu eklenemedi: " + err.Error())
+		}
+	}
+
+	// Katlama kuralları değiştiğinde (ör. şapkalı ünlüler) eski anahtarlar da güncellenir.
+	var rows []struct {
+		ID         uint
+		Account    string
+		AccountKey *string
+	}
+	if err := db.Table("users").Select("id, account, account_key").Find(&rows).Error; err != nil {
+		return errors.New("account_key doldurulacak kullanıcılar alınamadı: " + err.Error())
+	}
+
+	for _, row := range rows {
+		key := models.AccountKey(row.Account)
+		if row.AccountKey != nil && *row.AccountKey == key {
+			continue
+		}
+		if err := db.Table("users").Where("id = ?", row.ID).Update("account_key", key).Error; err != nil {
+			return errors.New("account_key doldurulamadı: " + err.Error())
+		}
 	}
 
-	_, err = rawDB.Exec(dropEnumQuery)
+	var duplicates []string
+	err := db.Table("users").
+		Select("account_key").
+		Group("account_key").
+		Having("COUNT(*) > 1").
+		Pluck("account_key", &duplicates).Error
 	if err != nil {
-		return errors.New("user_type enum silinemedi: " + err.Error())
+		return errors.New("account_key çakışmaları kontrol edilemedi: " + err.Error())
+	}
+	if len(duplicates) > 0 {
+		return errors.New("büyük/küçük harf farkıyla çakışan hesaplar var, migrasyondan önce birleştirilmeli: " + strings.Join(duplicates, ", "))
+	}
+
+	if migrator.HasConstraint(&usersTable{}, "uni_users_account") {
+		logconfig.SLog.Info("users.account üzerindeki eski benzersizlik kısıtı kaldırılıyor...")
+		if err := migrator.DropConstraint(&usersTable{}, "uni_users_account"); err != nil {
+			return errors.New("uni_users_account kısıtı kaldırılamadı: " + err.Error())
+		}
 	}
-	logconfig.SLog.Info("user_type enum başarıyla silindi.")
 
-	createEnum := `CREATE TYPE user_type AS ENUM ('dashboard', 'panel');`
-	_, err = rawDB.Exec(createEnum)
+	columns, err := migrator.ColumnTypes(&userEmailColumns{})
 	if err != nil {
-		return errors.New("user_type enum oluşturulamadı: " + err.Error())
+		return errors.New("account_key sütunu okunamadı: " + err.Error())
 	}
-	logconfig.SLog.Info("user_type enum başarıyla oluşturuldu.")
+	for _, column := range columns {
+		if nullable, ok := column.Nullable(); column.Name() == "account_key" && ok && nullable {
+			if err := migrator.AlterColumn(&userEmailColumns{}, "account_key"); err != nil {
+				return errors.New("account_key NOT NULL yapılamadı: " + err.Error())
+			}
+		}
+	}
+	return nil
+}
 
-	logconfig.SLog.Info("User tablosu migrate ediliyor...")
-	if err := db.AutoMigrate(&models.User{}); err != nil {
-		return errors.New("User tablosu migrate edilemedi: " + err.Error())
+func AddUsersSearchVector(db *gorm.DB) error {
+	logconfig.SLog.Info("User tablosu için tam metin arama sütunu kontrol ediliyor...")
+	return MigrateSearchVector(db, &userSearchColumns{})
+}
+
+func DropUsersSearchVector(db *gorm.DB) error {
+	if db.Dialector.Name() != "postgres" {
+		return nil
+	}
+	statements := []string{
+		"DROP INDEX IF EXISTS " + SearchVectorIndexName("users"),
+		"ALTER TABLE users DROP COLUMN IF EXISTS " + fulltext.VectorColumn,
 	}
+	for _, sql := range statements {
+		if err := db.Exec(sql).Error; err != nil {
+			return errors.New("users." + fulltext.VectorColumn + " kaldırılamadı: " + err.Error())
+		}
+	}
+	return nil
+}
 
-	logconfig.SLog.Info("User tablosu migrate işlemi tamamlandı.")
+func AddUsersVersion(db *gorm.DB) error {
+	logconfig.SLog.Info("users.version sütunu ekleniyor...")
+	if err := addColumns(db, &userVersionColumn{}, "version"); err != nil {
+		return errors.New("version sütunu eklenemedi: " + err.Error())
+	}
+	return restoreUserIndexes(db, &userTenantColumns{}, &userParentColumn{}, &userEmailColumns{})
+}
+
+func DropUsersVersion(db *gorm.DB) error {
+	if err := dropColumns(db, &userVersionColumn{}, "version"); err != nil {
+		return errors.New("version sütunu kaldırılamadı: " + err.Error())
+	}
+	return restoreUserIndexes(db, &userTenantColumns{}, &userParentColumn{}, &userEmailColumns{})
+}
+
+// restoreUserIndexes ilk tablonun ve verilen sütun gruplarının indekslerini tamamlar.
+func restoreUserIndexes(db *gorm.DB, snapshots ...interface{}) error {
+	if err := createIndexes(db, append([]interface{}{&usersTable{}}, snapshots...)...); err != nil {
+		return errors.New("users indeksleri oluşturulamadı: " + err.Error())
+	}
 	return nil
 }
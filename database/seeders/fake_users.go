@@ -0,0 +1,49 @@
+package seeders
+
+import (
+	"context"
+
+	"zatrano/configs/logconfig"
+	"zatrano/database/factories"
+	"zatrano/models"
+	"zatrano/pkg/faker"
+
+	"gorm.io/gorm"
+)
+
+const defaultFakeUserCount = 50
+
+// FakeUsersSeeder geliştirme veritabanını Türkçe isimli sahte kullanıcılarla doldurur.
+// Kullanıcıların bir kısmı sistem kullanıcısının altına yerleştirilir.
+type FakeUsersSeeder struct{}
+
+func (FakeUsersSeeder) Name() string        { return "fake_users" }
+func (FakeUsersSeeder) DependsOn() []string { return []string{"default_tenant"} }
+func (FakeUsersSeeder) Envs() []Env         { return []Env{EnvDev, EnvTest} }
+
+func (FakeUsersSeeder) Run(ctx context.Context, db *gorm.DB, opts Options) error {
+	systemUser, err := findSystemUser(db)
+	if err != nil {
+		return err
+	}
+
+	f := faker.New(opts.FakerSeed)
+	factory, err := factories.NewUserFactory(f)
+	if err != nil {
+		return err
+	}
+	factory = factory.With(func(u *models.User) {
+		u.TenantID = systemUser.TenantID
+		if f.Chance(0.3) {
+			u.ParentID = &systemUser.ID
+		}
+	})
+
+	count := opts.CountOr(defaultFakeUserCount)
+	ctx = context.WithValue(ctx, "user_id", systemUser.ID)
+	if _, err := factory.Create(ctx, db, count); err != nil {
+		return err
+	}
+	logconfig.SLog.Infof("%d sahte kullanıcı oluşturuldu (şifre: %s).", count, factories.DefaultUserPassword)
+	return nil
+}
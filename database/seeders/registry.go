@@ -0,0 +1,10 @@
+package seeders
+
+// All kayıtlı seeder'lardır. Sıra, bağımlılığı olmayan seeder'ların çalışma sırasını belirler.
+func All() []Seeder {
+	return []Seeder{
+		SystemUserSeeder{},
+		DefaultTenantSeeder{},
+		FakeUsersSeeder{},
+	}
+}
@@ -0,0 +1,179 @@
+package seeders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"zatrano/configs/logconfig"
+	"zatrano/models"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type Runner struct {
+	db      *gorm.DB
+	seeders []Seeder
+	byName  map[string]Seeder
+}
+
+// NewRunner seeder adlarının benzersiz, bağımlılıkların tanımlı ve döngüsüz olduğunu doğrular.
+func NewRunner(db *gorm.DB, seeders []Seeder) (*Runner, error) {
+	r := &Runner{db: db, seeders: seeders, byName: make(map[string]Seeder, len(seeders))}
+	for _, s := range seeders {
+		if _, ok := r.byName[s.Name()]; ok {
+			return nil, fmt.Errorf("aynı ada sahip iki seeder var: %s", s.Name())
+		}
+		r.byName[s.Name()] = s
+	}
+	for _, s := range seeders {
+		for _, dep := range s.DependsOn() {
+			if _, ok := r.byName[dep]; !ok {
+				return nil, fmt.Errorf("%s seeder'ının bağımlılığı bulunamadı: %s", s.Name(), dep)
+			}
+		}
+	}
+	if _, err := r.plan(r.names()); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Runner) Seeders() []Seeder {
+	return r.seeders
+}
+
+func (r *Runner) names() []string {
+	names := make([]string, 0, len(r.seeders))
+	for _, s := range r.seeders {
+		names = append(names, s.Name())
+	}
+	return names
+}
+
+// plan istenen seeder'ları bağımlılıklarıyla birlikte, bağımlılıklar önce gelecek
+// şekilde sıralar.
+func (r *Runner) plan(names []string) ([]Seeder, error) {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int, len(r.seeders))
+	var ordered []Seeder
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("seeder bağımlılıklarında döngü var: %v", append(path, name))
+		}
+		s, ok := r.byName[name]
+		if !ok {
+			return fmt.Errorf("seeder bulunamadı: %s", name)
+		}
+		state[name] = visiting
+		for _, dep := range s.DependsOn() {
+			if err := visit(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+		state[name] = done
+		ordered = append(ordered, s)
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// Run ad verilmezse ortamdaki tüm seeder'ları, verilirse yalnızca onları ve henüz
+// çalışmamış bağımlılıklarını çalıştırır; çalıştırılan seeder sayısını döner.
+// Tekrarlanabilir olmayan seeder'lar bir kez çalıştıktan sonra atlanır; adıyla
+// istenen seeder ise her zaman yeniden çalıştırılır.
+func (r *Runner) Run(ctx context.Context, opts Options, names ...string) (int, error) {
+	requested := make(map[string]bool, len(names))
+	for _, name := range names {
+		requested[name] = true
+	}
+	if len(names) == 0 {
+		names = r.names()
+	}
+
+	plan, err := r.plan(names)
+	if err != nil {
+		return 0, err
+	}
+
+	ran, err := r.ranSeeders(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, s := range plan {
+		if !allowedIn(s, opts.Env) {
+			if len(requested) > 0 {
+				return count, fmt.Errorf("%s seeder'ı %s ortamında çalıştırılamaz", s.Name(), opts.Env)
+			}
+			logconfig.SLog.Infof(" -> %s seeder'ı %s ortamı için değil, atlanıyor.", s.Name(), opts.Env)
+			continue
+		}
+		if ran[s.Name()] && !isRepeatable(s) && !requested[s.Name()] {
+			logconfig.SLog.Infof(" -> %s seeder'ı daha önce çalıştı, atlanıyor.", s.Name())
+			continue
+		}
+		if err := r.runOne(ctx, s, opts); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+func (r *Runner) runOne(ctx context.Context, s Seeder, opts Options) error {
+	start := time.Now()
+	logconfig.SLog.Infof(" -> %s seeder'ı çalıştırılıyor...", s.Name())
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.Run(ctx, tx, opts); err != nil {
+			return err
+		}
+		return tx.Save(&models.SeederRun{
+			Name:  s.Name(),
+			Env:   string(opts.Env),
+			Count: opts.Count,
+			RanAt: time.Now().UTC(),
+		}).Error
+	})
+	if err != nil {
+		logconfig.Log.Error("Seeder başarısız oldu", zap.String("seeder", s.Name()), zap.Error(err))
+		return fmt.Errorf("%s seeder'ı başarısız oldu: %w", s.Name(), err)
+	}
+
+	logconfig.Log.Info("Seeder tamamlandı", zap.String("seeder", s.Name()), zap.Duration("duration", time.Since(start)))
+	return nil
+}
+
+func (r *Runner) ranSeeders(ctx context.Context) (map[string]bool, error) {
+	var names []string
+	err := r.db.WithContext(ctx).Model(&models.SeederRun{}).Pluck("name", &names).Error
+	if err != nil {
+		if !r.db.Migrator().HasTable(&models.SeederRun{}) {
+			return nil, errors.New("seeder_runs tablosu yok, önce migrasyonları çalıştırın")
+		}
+		return nil, err
+	}
+	ran := make(map[string]bool, len(names))
+	for _, name := range names {
+		ran[name] = true
+	}
+	return ran, nil
+}
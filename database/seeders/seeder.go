@@ -0,0 +1,87 @@
+package seeders
+
+import (
+	"context"
+	"fmt"
+
+	"zatrano/configs/envconfig"
+
+	"gorm.io/gorm"
+)
+
+// Env seeder'ın çalışabileceği ortamdır.
+type Env string
+
+const (
+	EnvDev  Env = "dev"
+	EnvTest Env = "test"
+	EnvProd Env = "prod"
+)
+
+var AllEnvs = []Env{EnvDev, EnvTest, EnvProd}
+
+func ParseEnv(value string) (Env, error) {
+	for _, env := range AllEnvs {
+		if string(env) == value {
+			return env, nil
+		}
+	}
+	return "", fmt.Errorf("geçersiz seed ortamı %q (dev, test, prod)", value)
+}
+
+// DefaultEnv ortamı APP_ENV'den çıkarır: production → prod, test → test, diğerleri → dev.
+func DefaultEnv() Env {
+	switch envconfig.GetEnvWithDefault("APP_ENV", "development") {
+	case "production":
+		return EnvProd
+	case "test":
+		return EnvTest
+	default:
+		return EnvDev
+	}
+}
+
+type Options struct {
+	Env Env
+	// Count sahte veri üreten seeder'ların oluşturacağı kayıt sayısıdır; 0 ise seeder'ın
+	// kendi varsayılanı kullanılır.
+	Count int
+	// FakerSeed sahte verinin tohumudur; 0 ise her çalıştırmada farklı veri üretilir.
+	FakerSeed uint64
+}
+
+func (o Options) CountOr(fallback int) int {
+	if o.Count > 0 {
+		return o.Count
+	}
+	return fallback
+}
+
+// Seeder adıyla çalıştırılabilen bir veri yükleme adımıdır. DependsOn'daki seeder'lar
+// önce çalıştırılır; Envs dışındaki ortamlarda seeder atlanır.
+type Seeder interface {
+	Name() string
+	DependsOn() []string
+	Envs() []Env
+	Run(ctx context.Context, db *gorm.DB, opts Options) error
+}
+
+// Repeatable seeder'lar daha önce çalışmış olsalar da her seferinde yeniden çalışır;
+// idempotent olmaları gerekir.
+type Repeatable interface {
+	Repeatable() bool
+}
+
+func allowedIn(s Seeder, env Env) bool {
+	for _, e := range s.Envs() {
+		if e == env {
+			return true
+		}
+	}
+	return false
+}
+
+func isRepeatable(s Seeder) bool {
+	r, ok := s.(Repeatable)
+	return ok && r.Repeatable()
+}
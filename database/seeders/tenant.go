@@ -0,0 +1,75 @@
+package seeders
+
+import (
+	"context"
+	"zatrano/configs/logconfig"
+	"zatrano/models"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func GetDefaultTenantConfig() models.Tenant {
+	return models.Tenant{
+		Name:   "Varsayılan",
+		Slug:   "default",
+		Status: true,
+	}
+}
+
+// SeedDefaultTenant varsayılan kiracıyı oluşturur ve kiracısız kullanıcıları ona atar.
+// ctx işlemi yapan kullanıcının kimliğini (user_id) taşımalıdır.
+func SeedDefaultTenant(ctx context.Context, db *gorm.DB) (*models.Tenant, error) {
+	tenantConfig := GetDefaultTenantConfig()
+
+	var tenant models.Tenant
+	result := db.Where("slug = ?", tenantConfig.Slug).First(&tenant)
+	if result.Error == nil {
+		logconfig.SLog.Infof("Varsayılan kiracı '%s' zaten mevcut.", tenant.Slug)
+	} else if result.Error != gorm.ErrRecordNotFound {
+		logconfig.Log.Error("Varsayılan kiracı kontrol edilirken veritabanı hatası",
+			zap.String("slug", tenantConfig.Slug),
+			zap.Error(result.Error),
+		)
+		return nil, result.Error
+	} else {
+		tenant = tenantConfig
+		if err := db.WithContext(ctx).Create(&tenant).Error; err != nil {
+			logconfig.Log.Error("Varsayılan kiracı oluşturulamadı",
+				zap.String("slug", tenantConfig.Slug),
+				zap.Error(err),
+			)
+			return nil, err
+		}
+		logconfig.SLog.Infof("Varsayılan kiracı '%s' başarıyla oluşturuldu.", tenant.Slug)
+	}
+
+	assigned := db.WithContext(ctx).Model(&models.User{}).Where("tenant_id = ?", 0).Update("tenant_id", tenant.ID)
+	if assigned.Error != nil {
+		logconfig.Log.Error("Kiracısız kullanıcılar varsayılan kiracıya atanamadı", zap.Error(assigned.Error))
+		return nil, assigned.Error
+	}
+	if assigned.RowsAffected > 0 {
+		logconfig.SLog.Infof("%d kullanıcı varsayılan kiracıya atandı.", assigned.RowsAffected)
+	}
+
+	return &tenant, nil
+}
+
+// DefaultTenantSeeder varsayılan kiracıyı sistem kullanıcısı adına oluşturur ve sistem
+// kullanıcısı dahil kiracısız kullanıcıları ona atar.
+type DefaultTenantSeeder struct{}
+
+func (DefaultTenantSeeder) Name() string        { return "default_tenant" }
+func (DefaultTenantSeeder) DependsOn() []string { return []string{"system_user"} }
+func (DefaultTenantSeeder) Envs() []Env         { return AllEnvs }
+func (DefaultTenantSeeder) Repeatable() bool    { return true }
+
+func (DefaultTenantSeeder) Run(ctx context.Context, db *gorm.DB, opts Options) error {
+	systemUser, err := findSystemUser(db)
+	if err != nil {
+		return err
+	}
+	_, err = SeedDefaultTenant(context.WithValue(ctx, "user_id", systemUser.ID), db)
+	return err
+}
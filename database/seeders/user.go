@@ -12,10 +12,11 @@ import (
 
 func GetSystemUserConfig() models.User {
 	return models.User{
-		Name:     "ZATRANO",
-		Account:  "zatrano@zatrano",
-		Type:     models.Dashboard,
-		Password: "ZATRANO",
+		Name:         "ZATRANO",
+		Account:      "zatrano@zatrano",
+		Type:         models.Dashboard,
+		Password:     "ZATRANO",
+		IsSuperAdmin: true,
 	}
 }
 
@@ -32,15 +33,17 @@ func SeedSystemUser(db *gorm.DB) error {
 	}
 
 	userToSeed := models.User{
-		Name:     systemUserConfig.Name,
-		Account:  systemUserConfig.Account,
-		Type:     systemUserConfig.Type,
-		Password: string(hashedPassword),
-		Status:   true,
+		Name:         systemUserConfig.Name,
+		Account:      systemUserConfig.Account,
+		AccountKey:   models.AccountKey(systemUserConfig.Account),
+		Type:         systemUserConfig.Type,
+		Password:     string(hashedPassword),
+		Status:       true,
+		IsSuperAdmin: systemUserConfig.IsSuperAdmin,
 	}
 
 	var existingUser models.User
-	result := db.Where("account = ? AND type = ?", userToSeed.Account, userToSeed.Type).First(&existingUser)
+	result := db.Where("account_key = ? AND type = ?", userToSeed.AccountKey, userToSeed.Type).First(&existingUser)
 
 	if result.Error == nil {
 		logconfig.SLog.Info("Sistem kullanıcısı '%s' zaten mevcut. Güncelleme gerekip gerekmediği kontrol ediliyor...", userToSeed.Account)
@@ -56,6 +59,10 @@ func SeedSystemUser(db *gorm.DB) error {
 			updateFields["status"] = true
 			needsUpdate = true
 		}
+		if existingUser.IsSuperAdmin != userToSeed.IsSuperAdmin {
+			updateFields["is_super_admin"] = userToSeed.IsSuperAdmin
+			needsUpdate = true
+		}
 
 		if needsUpdate {
 			logconfig.SLog.Info("Mevcut sistem kullanıcısı '%s' güncelleniyor...", userToSeed.Account)
@@ -95,6 +102,40 @@ func SeedSystemUser(db *gorm.DB) error {
 		return err
 	}
 
+	hierarchyRoot := models.UserHierarchy{AncestorID: userToSeed.ID, DescendantID: userToSeed.ID}
+	if err := db.Create(&hierarchyRoot).Error; err != nil {
+		logconfig.Log.Error("Sistem kullanıcısı hiyerarşiye eklenemedi",
+			zap.String("account", userToSeed.Account),
+			zap.Error(err),
+		)
+		return err
+	}
+
 	logconfig.SLog.Info("Sistem kullanıcısı '%s' başarıyla oluşturuldu.", userToSeed.Account)
 	return nil
 }
+
+// findSystemUser system_user seeder'ının oluşturduğu kullanıcıyı okur; sonraki
+// seeder'lar kayıtları onun adına oluşturur.
+func findSystemUser(db *gorm.DB) (*models.User, error) {
+	systemUserConfig := GetSystemUserConfig()
+	var systemUser models.User
+	err := db.Where("account_key = ? AND type = ?", models.AccountKey(systemUserConfig.Account), systemUserConfig.Type).
+		First(&systemUser).Error
+	if err != nil {
+		return nil, err
+	}
+	return &systemUser, nil
+}
+
+// SystemUserSeeder sistem kullanıcısını oluşturur ya da ayarlarını günceller.
+type SystemUserSeeder struct{}
+
+func (SystemUserSeeder) Name() string        { return "system_user" }
+func (SystemUserSeeder) DependsOn() []string { return nil }
+func (SystemUserSeeder) Envs() []Env         { return AllEnvs }
+func (SystemUserSeeder) Repeatable() bool    { return true }
+
+func (SystemUserSeeder) Run(ctx context.Context, db *gorm.DB, opts Options) error {
+	return SeedSystemUser(db)
+}
@@ -1,12 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"zatrano/configs/logconfig"
 	"zatrano/configs/sessionconfig"
 	"zatrano/models"
+	"zatrano/pkg/apperrors"
 	"zatrano/pkg/flashmessages"
+	"zatrano/pkg/passwordpolicy"
 	"zatrano/pkg/renderer"
 	"zatrano/requests"
 	"zatrano/services"
@@ -16,11 +19,17 @@ import (
 )
 
 type AuthHandler struct {
-	service services.IAuthService
+	service           services.IAuthService
+	invitationService services.IInvitationService
+	emailService      services.IEmailVerificationService
 }
 
 func NewAuthHandler() *AuthHandler {
-	return &AuthHandler{service: services.NewAuthService()}
+	return &AuthHandler{
+		service:           services.NewAuthService(),
+		invitationService: services.NewInvitationService(),
+		emailService:      services.NewEmailVerificationService(),
+	}
 }
 
 func (h *AuthHandler) handleError(c *fiber.Ctx, err error, userID uint, account string, action string) error {
@@ -29,20 +38,20 @@ func (h *AuthHandler) handleError(c *fiber.Ctx, err error, userID uint, account
 	redirectTarget := "/auth/login"
 	logoutUser := false
 
-	switch err {
-	case services.ErrInvalidCredentials:
+	switch {
+	case errors.Is(err, services.ErrInvalidCredentials):
 		errMsg = "Kullanıcı adı veya şifre hatalı."
-	case services.ErrUserInactive:
+	case errors.Is(err, services.ErrUserInactive):
 		errMsg = "Hesabınız aktif değil. Lütfen yöneticinizle iletişime geçin."
-	case services.ErrUserNotFound:
+	case errors.Is(err, services.ErrUserNotFound):
 		errMsg = "Kullanıcı bulunamadı, lütfen tekrar giriş yapın."
 		logoutUser = true
 		logconfig.Log.Warn(action+": Kullanıcı bulunamadı", zap.Uint("user_id", userID))
-	case services.ErrCurrentPasswordIncorrect:
+	case errors.Is(err, services.ErrCurrentPasswordIncorrect):
 		errMsg = "Mevcut şifreniz hatalı."
 		redirectTarget = "/auth/profile"
-	case services.ErrPasswordTooShort, services.ErrPasswordSameAsOld:
-		errMsg = err.Error()
+	case apperrors.IsKind(err, apperrors.KindValidation):
+		errMsg = apperrors.Message(err)
 		redirectTarget = "/auth/profile"
 	default:
 		errMsg = "İşlem sırasında bir sorun oluştu. Lütfen tekrar deneyin."
@@ -126,11 +135,18 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 		return c.Redirect("/auth/login", fiber.StatusSeeOther)
 	}
 
-	user, err := h.service.Authenticate(req.Account, req.Password)
+	user, err := h.service.Authenticate(c.UserContext(), req.Account, req.Password)
 	if err != nil {
 		return h.handleError(c, err, 0, req.Account, "Login")
 	}
 
+	if tenantID, ok := c.Locals("tenantID").(uint); ok && !user.IsSuperAdmin && tenantID != user.TenantID {
+		logconfig.Log.Warn("Login: Kullanıcı bu kiracıya ait değil",
+			zap.Uint("user_id", user.ID),
+			zap.Uint("tenant_id", tenantID))
+		return h.handleError(c, services.ErrInvalidCredentials, user.ID, user.Account, "Login")
+	}
+
 	sess, err := sessionconfig.SessionStart(c)
 	if err != nil {
 		logconfig.Log.Error("Oturum başlatılamadı",
@@ -142,6 +158,11 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 
 	sess.Set("user_id", user.ID)
 	sess.Set("user_type", string(user.Type))
+	if user.IsSuperAdmin {
+		sess.Delete("tenant_id")
+	} else {
+		sess.Set("tenant_id", user.TenantID)
+	}
 	if err := sess.Save(); err != nil {
 		logconfig.Log.Error("Oturum kaydedilemedi",
 			zap.Uint("user_id", user.ID),
@@ -173,7 +194,7 @@ func (h *AuthHandler) Profile(c *fiber.Ctx) error {
 		return c.Redirect("/auth/login", fiber.StatusSeeOther)
 	}
 
-	user, err := h.service.GetUserProfile(userID)
+	user, err := h.service.GetUserProfile(c.UserContext(), userID)
 	if err != nil {
 		return h.handleError(c, err, userID, "", "Profil")
 	}
@@ -206,7 +227,7 @@ func (h *AuthHandler) UpdatePassword(c *fiber.Ctx) error {
 		return c.Redirect("/auth/profile", fiber.StatusSeeOther)
 	}
 
-	if err := h.service.UpdatePassword(userID, req.CurrentPassword, req.NewPassword); err != nil {
+	if err := h.service.UpdatePassword(c.UserContext(), userID, req.CurrentPassword, req.NewPassword); err != nil {
 		return h.handleError(c, err, userID, "", "Parola Güncelleme")
 	}
 
@@ -214,3 +235,108 @@ func (h *AuthHandler) UpdatePassword(c *fiber.Ctx) error {
 	_ = flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "Şifre başarıyla güncellendi. Lütfen yeni şifrenizle tekrar giriş yapın.")
 	return c.Redirect("/auth/login", fiber.StatusFound)
 }
+
+func (h *AuthHandler) ShowInvitation(c *fiber.Ctx) error {
+	token := c.Params("token")
+	mapData := fiber.Map{
+		"Title":             "Daveti Kabul Et",
+		"Token":             token,
+		"PasswordMinLength": passwordpolicy.MinLength(),
+	}
+
+	invitation, err := h.invitationService.GetInvitationByToken(c.UserContext(), token)
+	if err != nil {
+		mapData[renderer.FlashErrorKeyView] = invitationErrorMessage(err)
+		return renderer.Render(c, "auth/invitation", "layouts/auth", mapData, http.StatusGone)
+	}
+
+	mapData["Invitation"] = invitation
+	return renderer.Render(c, "auth/invitation", "layouts/auth", mapData, http.StatusOK)
+}
+
+func (h *AuthHandler) AcceptInvitation(c *fiber.Ctx) error {
+	token := c.Params("token")
+	var req struct {
+		Password        string `form:"password"`
+		ConfirmPassword string `form:"confirm_password"`
+	}
+	_ = c.BodyParser(&req)
+
+	err := h.invitationService.AcceptInvitation(c.UserContext(), token, req.Password, req.ConfirmPassword)
+	if err != nil {
+		mapData := fiber.Map{
+			"Title":                    "Daveti Kabul Et",
+			"Token":                    token,
+			"PasswordMinLength":        passwordpolicy.MinLength(),
+			renderer.FlashErrorKeyView: invitationErrorMessage(err),
+		}
+		status := http.StatusGone
+		if invitation, lookupErr := h.invitationService.GetInvitationByToken(c.UserContext(), token); lookupErr == nil {
+			mapData["Invitation"] = invitation
+			status = http.StatusBadRequest
+		}
+		return renderer.Render(c, "auth/invitation", "layouts/auth", mapData, status)
+	}
+
+	_ = flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "Şifreniz belirlendi, hesabınız etkinleştirildi. Giriş yapabilirsiniz.")
+	return c.Redirect("/auth/login", fiber.StatusFound)
+}
+
+func (h *AuthHandler) RequestEmailChange(c *fiber.Ctx) error {
+	userID, err := h.getSessionUser(c)
+	if err != nil {
+		h.destroySession(c)
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Geçersiz oturum bilgisi, lütfen tekrar giriş yapın.")
+		return c.Redirect("/auth/login", fiber.StatusSeeOther)
+	}
+
+	email := c.FormValue("email")
+	if err := h.emailService.RequestEmailChange(c.UserContext(), userID, email, c.BaseURL()); err != nil {
+		switch apperrors.KindOf(err) {
+		case apperrors.KindConflict, apperrors.KindValidation:
+			_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, apperrors.Message(err))
+		default:
+			_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "E-posta değişikliği başlatılamadı. Lütfen tekrar deneyin.")
+		}
+		return c.Redirect("/auth/profile", fiber.StatusSeeOther)
+	}
+
+	_ = flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "Yeni adresinize bir doğrulama bağlantısı gönderildi. Bağlantıyı onayladığınızda e-posta adresiniz güncellenecek.")
+	return c.Redirect("/auth/profile", fiber.StatusFound)
+}
+
+func (h *AuthHandler) ConfirmEmailChange(c *fiber.Ctx) error {
+	if _, err := h.emailService.ConfirmEmailChange(c.UserContext(), c.Params("token")); err != nil {
+		var errMsg string
+		switch {
+		case errors.Is(err, services.ErrEmailVerificationExpired):
+			errMsg = "E-posta doğrulama bağlantısının süresi dolmuş ya da bağlantı daha önce kullanılmış."
+		case errors.Is(err, services.ErrEmailVerificationInvalid):
+			errMsg = "E-posta doğrulama bağlantısı geçersiz."
+		case errors.Is(err, services.ErrEmailTaken):
+			errMsg = "Bu e-posta adresi artık başka bir hesap tarafından kullanılıyor."
+		default:
+			errMsg = "E-posta adresi doğrulanamadı. Lütfen tekrar deneyin."
+		}
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, errMsg)
+		return c.Redirect("/auth/login", fiber.StatusSeeOther)
+	}
+
+	_ = flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "E-posta adresiniz doğrulandı ve güncellendi.")
+	return c.Redirect("/auth/login", fiber.StatusFound)
+}
+
+func invitationErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, services.ErrInvitationExpired):
+		return "Davet bağlantısının süresi dolmuş."
+	case errors.Is(err, services.ErrInvitationNotPending):
+		return "Bu davet daha önce kullanılmış ya da iptal edilmiş."
+	case errors.Is(err, services.ErrInvitationInvalid):
+		return "Davet bağlantısı geçersiz."
+	case errors.Is(err, passwordpolicy.ErrPasswordPolicy):
+		return apperrors.Message(err)
+	default:
+		return "İşlem sırasında bir sorun oluştu. Lütfen tekrar deneyin."
+	}
+}
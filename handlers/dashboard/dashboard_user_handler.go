@@ -1,10 +1,16 @@
 package handlers
 
 import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
 	"net/http"
 	"strings"
 	"zatrano/configs/logconfig"
 	"zatrano/models"
+	"zatrano/pkg/apperrors"
+	"zatrano/pkg/exporter"
 	"zatrano/pkg/flashmessages"
 	"zatrano/pkg/queryparams"
 	"zatrano/pkg/renderer"
@@ -15,15 +21,27 @@ import (
 )
 
 type UserHandler struct {
-	userService services.IUserService
+	userService       services.IUserService
+	tenantService     services.ITenantService
+	hierarchyService  services.IUserHierarchyService
+	invitationService services.IInvitationService
+	emailService      services.IEmailVerificationService
 }
 
 func NewUserHandler() *UserHandler {
 	svc := services.NewUserService()
-	return &UserHandler{userService: svc}
+	return &UserHandler{
+		userService:       svc,
+		tenantService:     services.NewTenantService(),
+		hierarchyService:  services.NewUserHierarchyService(),
+		invitationService: services.NewInvitationService(),
+		emailService:      services.NewEmailVerificationService(),
+	}
 }
 
-func (h *UserHandler) ListUsers(c *fiber.Ctx) error {
+// parseListParams sayfalama/sıralama parametrelerini ve filtreleri okur. Filtre
+// sözdizimi hatalıysa varsayılan sayfalama ile birlikte doğrulama hatası döner.
+func parseListParams(c *fiber.Ctx, extraReserved ...string) (queryparams.ListParams, error) {
 	var params queryparams.ListParams
 	if err := c.QueryParser(&params); err != nil {
 		logconfig.Log.Warn("Kullanıcı listesi: Query parametreleri parse edilemedi, varsayılanlar kullanılıyor.", zap.Error(err))
@@ -43,78 +61,250 @@ func (h *UserHandler) ListUsers(c *fiber.Ctx) error {
 		params.OrderBy = queryparams.DefaultOrderBy
 	}
 
-	paginatedResult, dbErr := h.userService.GetAllUsers(params)
+	filters, err := queryparams.ParseFilters(c.Queries(), extraReserved...)
+	params.Filters = filters
+	return params, err
+}
+
+// ListUsers "Accept: application/json" isteklerinde sonucu JSON döner; hatalar bu
+// durumda genel hata işleyicisine bırakılır.
+func (h *UserHandler) ListUsers(c *fiber.Ctx) error {
+	params, dbErr := parseListParams(c)
+	var result any
+	var users []models.User
+	if dbErr == nil {
+		result, users, dbErr = h.fetchUsers(c, params)
+	}
+
+	if strings.Contains(c.Get("Accept"), "application/json") {
+		if dbErr != nil {
+			return dbErr
+		}
+		return c.JSON(result)
+	}
 
+	currentUserID, _ := c.Locals("userID").(uint)
 	renderData := fiber.Map{
-		"Title":  "Kullanıcılar",
-		"Result": paginatedResult,
-		"Params": params,
-	}
-	if dbErr != nil {
-		logconfig.Log.Error("Kullanıcı listesi DB Hatası", zap.Error(dbErr))
-		renderData[renderer.FlashErrorKeyView] = "Kullanıcılar getirilirken bir hata oluştu."
-		renderData["Result"] = &queryparams.PaginatedResult{
-			Data: []models.User{},
-			Meta: queryparams.PaginationMeta{
-				CurrentPage: params.Page, PerPage: params.PerPage,
-			},
+		"Title":         "Kullanıcılar",
+		"Result":        result,
+		"CursorMode":    params.UsesCursor(),
+		"Params":        params,
+		"ExportColumns": services.UserExportColumns,
+		"ExportJobs":    exporter.Jobs.ListByOwner(currentUserID),
+	}
+	status := http.StatusOK
+	if dbErr == nil {
+		renderData["Invitations"] = h.latestInvitations(c, users)
+	} else {
+		if apperrors.IsKind(dbErr, apperrors.KindValidation) {
+			renderData[renderer.FlashErrorKeyView] = "Geçersiz filtre: " + apperrors.Message(dbErr)
+		} else {
+			logconfig.Log.Error("Kullanıcı listesi DB Hatası", zap.Error(dbErr))
+			renderData[renderer.FlashErrorKeyView] = "Kullanıcılar getirilirken bir hata oluştu."
+		}
+		status = apperrors.HTTPStatus(dbErr)
+		if params.UsesCursor() {
+			renderData["Result"] = &queryparams.CursorResult{
+				Data: []models.User{},
+				Meta: queryparams.CursorMeta{PerPage: params.PerPage},
+			}
+		} else {
+			renderData["Result"] = &queryparams.PaginatedResult{
+				Data: []models.User{},
+				Meta: queryparams.PaginationMeta{
+					CurrentPage: params.Page, PerPage: params.PerPage,
+				},
+			}
 		}
 	}
-	return renderer.Render(c, "dashboard/users/list", "layouts/dashboard", renderData, http.StatusOK)
+	return renderer.Render(c, "dashboard/users/list", "layouts/dashboard", renderData, status)
 }
 
-func (h *UserHandler) ShowCreateUser(c *fiber.Ctx) error {
-	return renderer.Render(c, "dashboard/users/create", "layouts/dashboard", fiber.Map{
-		"Title": "Yeni Kullanıcı Ekle",
+// fetchUsers listeyi istenen sayfalama moduna göre getirir.
+func (h *UserHandler) fetchUsers(c *fiber.Ctx, params queryparams.ListParams) (any, []models.User, error) {
+	if params.UsesCursor() {
+		page, err := h.userService.GetUsersByCursor(c.UserContext(), params)
+		if err != nil {
+			return nil, nil, err
+		}
+		users, _ := page.Data.([]models.User)
+		return page, users, nil
+	}
+
+	page, err := h.userService.GetAllUsers(c.UserContext(), params)
+	if err != nil {
+		return nil, nil, err
+	}
+	users, _ := page.Data.([]models.User)
+	return page, users, nil
+}
+
+func (h *UserHandler) ExportUsers(c *fiber.Ctx) error {
+	params, err := parseListParams(c, "format", "columns", "locale")
+	if err != nil {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Geçersiz filtre: "+apperrors.Message(err))
+		return c.Redirect("/dashboard/users", fiber.StatusSeeOther)
+	}
+	opts, err := exporter.ParseOptions(c.Query("format"), c.Query("columns"), c.Query("locale"))
+	if err != nil {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Geçersiz dışa aktarma formatı.")
+		return c.Redirect("/dashboard/users", fiber.StatusSeeOther)
+	}
+	if _, err := exporter.SelectColumns(services.UserExportColumns, opts.Columns); err != nil {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Geçersiz dışa aktarma sütunu seçildi.")
+		return c.Redirect("/dashboard/users", fiber.StatusSeeOther)
+	}
+
+	count, err := h.userService.GetFilteredUserCount(c.UserContext(), params)
+	if err != nil {
+		if apperrors.IsKind(err, apperrors.KindValidation) {
+			_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Geçersiz filtre: "+apperrors.Message(err))
+			return c.Redirect("/dashboard/users", fiber.StatusSeeOther)
+		}
+		logconfig.Log.Error("Dışa aktarma: Kayıt sayısı alınamadı", zap.Error(err))
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Dışa aktarma başlatılamadı.")
+		return c.Redirect("/dashboard/users", fiber.StatusSeeOther)
+	}
+
+	// Yanıt gövdesi handler döndükten sonra yazıldığı için istek iptalinden bağımsız bir context kullanılır.
+	ctx := context.WithoutCancel(c.UserContext())
+	fileName := exporter.FileName("kullanicilar", opts.Format)
+
+	if count > exporter.AsyncThreshold() {
+		currentUserID, _ := c.Locals("userID").(uint)
+		job, err := exporter.Jobs.Start(currentUserID, fileName, opts.Format, func(w io.Writer) error {
+			return h.userService.ExportUsers(ctx, params, opts, w)
+		})
+		if err != nil {
+			logconfig.Log.Error("Dışa aktarma işi başlatılamadı", zap.Error(err))
+			_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Dışa aktarma başlatılamadı.")
+			return c.Redirect("/dashboard/users", fiber.StatusSeeOther)
+		}
+
+		if strings.Contains(c.Get("Accept"), "application/json") {
+			return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+				"job_id":       job.ID,
+				"status":       job.Status,
+				"download_url": "/dashboard/users/exports/" + job.ID,
+			})
+		}
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "Dışa aktarma arka planda hazırlanıyor. Hazır olduğunda indirme bağlantısı listede görünecek.")
+		return c.Redirect("/dashboard/users", fiber.StatusFound)
+	}
+
+	c.Set(fiber.HeaderContentType, opts.Format.ContentType())
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+fileName+`"`)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := h.userService.ExportUsers(ctx, params, opts, w); err != nil {
+			logconfig.Log.Error("Dışa aktarma akışı yarıda kaldı", zap.Error(err))
+		}
+		_ = w.Flush()
 	})
+	return nil
 }
 
-func (h *UserHandler) CreateUser(c *fiber.Ctx) error {
+func (h *UserHandler) DownloadExport(c *fiber.Ctx) error {
+	currentUserID, _ := c.Locals("userID").(uint)
+	job, ok := exporter.Jobs.Get(c.Params("id"))
+	if !ok || job.OwnerID != currentUserID {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Dışa aktarma dosyası bulunamadı.")
+		return c.Redirect("/dashboard/users", fiber.StatusSeeOther)
+	}
+
+	if strings.Contains(c.Get("Accept"), "application/json") {
+		return c.JSON(fiber.Map{"job_id": job.ID, "status": job.Status, "error": job.Error})
+	}
+
+	switch job.Status {
+	case exporter.JobCompleted:
+		return c.Download(job.Path(), job.FileName)
+	case exporter.JobFailed:
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Dışa aktarma başarısız oldu: "+job.Error)
+	default:
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "Dışa aktarma dosyası henüz hazırlanıyor, lütfen biraz sonra tekrar deneyin.")
+	}
+	return c.Redirect("/dashboard/users", fiber.StatusSeeOther)
+}
+
+func (h *UserHandler) ShowInviteUser(c *fiber.Ctx) error {
+	return renderer.Render(c, "dashboard/users/invite", "layouts/dashboard", h.withParentOptions(c, 0, h.withTenantOptions(c, fiber.Map{
+		"Title": "Kullanıcı Davet Et",
+	})))
+}
+
+func (h *UserHandler) InviteUser(c *fiber.Ctx) error {
 	var req struct {
 		Name     string `form:"name"`
 		Account  string `form:"account"`
-		Password string `form:"password"`
-		Status   string `form:"status"`
+		Email    string `form:"email"`
 		Type     string `form:"type"`
+		TenantID uint   `form:"tenant_id"`
+		ParentID uint   `form:"parent_id"`
 	}
 	_ = c.BodyParser(&req)
 
-	if req.Name == "" || req.Account == "" || req.Password == "" || req.Type == "" {
-		return renderUserFormError("Yeni Kullanıcı Ekle", req, "Ad, Hesap Adı, Şifre ve Kullanıcı Tipi alanları zorunludur.", c)
+	if req.Name == "" || req.Account == "" || req.Email == "" || req.Type == "" {
+		return h.renderUserFormError(c, "Kullanıcı Davet Et", req, "Ad, Hesap Adı, E-posta ve Kullanıcı Tipi alanları zorunludur.", nil)
 	}
 
-	status := req.Status == "true"
 	user := &models.User{
 		Name:     req.Name,
 		Account:  req.Account,
-		Password: req.Password,
-		Status:   status,
 		Type:     models.UserType(req.Type),
+		ParentID: optionalID(req.ParentID),
+	}
+	if isSuperAdmin, _ := c.Locals("isSuperAdmin").(bool); isSuperAdmin && req.TenantID > 0 {
+		user.TenantID = req.TenantID
 	}
 
 	if user.Type != models.Dashboard && user.Type != models.Panel {
-		return renderUserFormError("Yeni Kullanıcı Ekle", req, "Geçersiz kullanıcı tipi seçildi.", c)
+		return h.renderUserFormError(c, "Kullanıcı Davet Et", req, "Geçersiz kullanıcı tipi seçildi.", nil)
 	}
 
-	if err := h.userService.CreateUser(c.UserContext(), user); err != nil {
-		return renderUserFormError("Yeni Kullanıcı Ekle", req, "Kullanıcı oluşturulamadı: "+err.Error(), c)
+	invitation, err := h.invitationService.InviteUser(c.UserContext(), user, req.Email, c.BaseURL())
+	if err != nil && invitation == nil {
+		return h.renderUserFormError(c, "Kullanıcı Davet Et", req, "Davet oluşturulamadı: "+apperrors.Message(err), apperrors.Fields(err))
+	}
+	if err != nil {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Kullanıcı oluşturuldu ancak davet gönderilemedi: "+apperrors.Message(err)+" Listeden tekrar gönderebilirsiniz.")
+		return c.Redirect("/dashboard/users", fiber.StatusSeeOther)
 	}
 
-	_ = flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "Kullanıcı başarıyla oluşturuldu.")
+	_ = flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "Davet "+req.Email+" adresine gönderildi.")
+	return c.Redirect("/dashboard/users", fiber.StatusFound)
+}
+
+func (h *UserHandler) ResendInvitation(c *fiber.Ctx) error {
+	id, _ := c.ParamsInt("id")
+	if err := h.invitationService.ResendInvitation(c.UserContext(), uint(id), c.BaseURL()); err != nil {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Davet tekrar gönderilemedi: "+apperrors.Message(err))
+		return c.Redirect("/dashboard/users", fiber.StatusSeeOther)
+	}
+	_ = flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "Davet tekrar gönderildi. Önceki bağlantılar artık geçersiz.")
+	return c.Redirect("/dashboard/users", fiber.StatusFound)
+}
+
+func (h *UserHandler) RevokeInvitation(c *fiber.Ctx) error {
+	id, _ := c.ParamsInt("id")
+	if err := h.invitationService.RevokeInvitation(c.UserContext(), uint(id)); err != nil {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Davet iptal edilemedi: "+apperrors.Message(err))
+		return c.Redirect("/dashboard/users", fiber.StatusSeeOther)
+	}
+	_ = flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "Davet iptal edildi.")
 	return c.Redirect("/dashboard/users", fiber.StatusFound)
 }
 
 func (h *UserHandler) ShowUpdateUser(c *fiber.Ctx) error {
 	id, _ := c.ParamsInt("id")
-	user, err := h.userService.GetUserByID(uint(id))
+	user, err := h.userService.GetUserByID(c.UserContext(), uint(id))
 	if err != nil {
 		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Kullanıcı bulunamadı.")
 		return c.Redirect("/dashboard/users", fiber.StatusSeeOther)
 	}
-	return renderer.Render(c, "dashboard/users/update", "layouts/dashboard", fiber.Map{
+	return renderer.Render(c, "dashboard/users/update", "layouts/dashboard", h.withHistory(c, user.ID, h.withParentOptions(c, user.ID, fiber.Map{
 		"Title": "Kullanıcı Düzenle",
 		"User":  user,
-	})
+	})))
 }
 
 func (h *UserHandler) UpdateUser(c *fiber.Ctx) error {
@@ -124,54 +314,106 @@ func (h *UserHandler) UpdateUser(c *fiber.Ctx) error {
 	var req struct {
 		Name     string `form:"name"`
 		Account  string `form:"account"`
+		Email    string `form:"email"`
 		Password string `form:"password"`
 		Status   string `form:"status"`
 		Type     string `form:"type"`
+		ParentID uint   `form:"parent_id"`
+		Version  uint   `form:"version"`
 	}
 	_ = c.BodyParser(&req)
 
 	if req.Name == "" || req.Account == "" || req.Type == "" {
-		user, _ := h.userService.GetUserByID(userID)
-		return renderer.Render(c, "dashboard/users/update", "layouts/dashboard", fiber.Map{
-			"Title":                    "Kullanıcı Düzenle",
-			renderer.FlashErrorKeyView: "Zorunlu alanlar eksik.",
-			renderer.FormDataKey:       req,
-			"User":                     user,
-		}, http.StatusBadRequest)
+		return h.renderUpdateFormError(c, userID, req, "Zorunlu alanlar eksik.", nil, http.StatusBadRequest)
 	}
 
 	userData := &models.User{
-		Name:    req.Name,
-		Account: req.Account,
-		Status:  req.Status == "true",
-		Type:    models.UserType(req.Type),
+		Name:     req.Name,
+		Account:  req.Account,
+		Status:   req.Status == "true",
+		Type:     models.UserType(req.Type),
+		ParentID: optionalID(req.ParentID),
 	}
+	userData.Version = req.Version
 	if req.Password != "" {
 		userData.Password = req.Password
 	}
 
+	current, err := h.userService.GetUserByID(c.UserContext(), userID)
+	if err != nil {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Kullanıcı bulunamadı.")
+		return c.Redirect("/dashboard/users", fiber.StatusSeeOther)
+	}
+
 	if err := h.userService.UpdateUser(c.UserContext(), userID, userData); err != nil {
-		user, _ := h.userService.GetUserByID(userID)
-		return renderer.Render(c, "dashboard/users/update", "layouts/dashboard", fiber.Map{
-			"Title":                    "Kullanıcı Düzenle",
-			renderer.FlashErrorKeyView: "Güncelleme hatası: " + err.Error(),
-			renderer.FormDataKey:       req,
-			"User":                     user,
-		}, http.StatusInternalServerError)
+		if errors.Is(err, services.ErrUserModified) {
+			return h.renderUpdateConflict(c, userID, req)
+		}
+		return h.renderUpdateFormError(c, userID, req, "Güncelleme hatası: "+apperrors.Message(err), apperrors.Fields(err), apperrors.HTTPStatus(err))
+	}
+
+	email := models.NormalizeEmail(req.Email)
+	if email != "" && email != current.EmailAddress() {
+		if err := h.emailService.RequestEmailChange(c.UserContext(), userID, email, c.BaseURL()); err != nil {
+			return h.renderUpdateFormError(c, userID, req, "Diğer değişiklikler kaydedildi ancak e-posta değiştirilemedi: "+apperrors.Message(err), apperrors.Fields(err), apperrors.HTTPStatus(err))
+		}
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "Kullanıcı güncellendi. Yeni e-posta adresi, "+email+" adresine gönderilen bağlantı onaylandığında geçerli olacak.")
+		return c.Redirect("/dashboard/users", fiber.StatusFound)
 	}
 
 	_ = flashmessages.SetFlashMessage(c, flashmessages.FlashSuccessKey, "Kullanıcı başarıyla güncellendi.")
 	return c.Redirect("/dashboard/users", fiber.StatusFound)
 }
 
+func (h *UserHandler) renderUpdateFormError(c *fiber.Ctx, userID uint, req any, message string, fieldErrors map[string]string, status int) error {
+	user, _ := h.userService.GetUserByID(c.UserContext(), userID)
+	return renderer.Render(c, "dashboard/users/update", "layouts/dashboard", h.withHistory(c, userID, h.withParentOptions(c, userID, fiber.Map{
+		"Title":                    "Kullanıcı Düzenle",
+		renderer.FlashErrorKeyView: message,
+		renderer.FormDataKey:       req,
+		"FieldErrors":              fieldErrors,
+		"User":                     user,
+	})), status)
+}
+
+// renderUpdateConflict formu, gönderilen değerlerle birlikte veritabanındaki güncel
+// değerleri yan yana gösterecek şekilde yeniden çizer. Formdaki sürüm güncel sürüme
+// çekilir; yönetici farkları gördükten sonra tekrar kaydederse kendi değerleri yazılır.
+func (h *UserHandler) renderUpdateConflict(c *fiber.Ctx, userID uint, req any) error {
+	current, err := h.userService.GetUserByID(c.UserContext(), userID)
+	if err != nil {
+		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Kullanıcı bulunamadı.")
+		return c.Redirect("/dashboard/users", fiber.StatusSeeOther)
+	}
+	return renderer.Render(c, "dashboard/users/update", "layouts/dashboard", h.withHistory(c, userID, h.withParentOptions(c, userID, fiber.Map{
+		"Title":                    "Kullanıcı Düzenle",
+		renderer.FlashErrorKeyView: "Kullanıcı siz düzenlerken başka biri tarafından değiştirildi. Güncel değerleri kontrol edip tekrar kaydedin.",
+		renderer.FormDataKey:       req,
+		"Conflict":                 true,
+		"User":                     current,
+	})), http.StatusConflict)
+}
+
+func (h *UserHandler) ShowUserTree(c *fiber.Ctx) error {
+	tree, err := h.hierarchyService.GetTree(c.UserContext())
+	renderData := fiber.Map{
+		"Title": "Kullanıcı Hiyerarşisi",
+		"Tree":  tree,
+	}
+	if err != nil {
+		renderData[renderer.FlashErrorKeyView] = "Kullanıcı hiyerarşisi getirilirken bir hata oluştu."
+	}
+	return renderer.Render(c, "dashboard/users/tree", "layouts/dashboard", renderData, http.StatusOK)
+}
+
 func (h *UserHandler) DeleteUser(c *fiber.Ctx) error {
 	id, _ := c.ParamsInt("id")
 	userID := uint(id)
 
 	if err := h.userService.DeleteUser(c.UserContext(), userID); err != nil {
-		errMsg := "Kullanıcı silinemedi: " + err.Error()
+		errMsg := "Kullanıcı silinemedi: " + apperrors.Message(err)
 		if strings.Contains(c.Get("Accept"), "application/json") {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": errMsg})
+			return c.Status(apperrors.HTTPStatus(err)).JSON(fiber.Map{"error": errMsg})
 		}
 		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, errMsg)
 		return c.Redirect("/dashboard/users", fiber.StatusSeeOther)
@@ -184,10 +426,78 @@ func (h *UserHandler) DeleteUser(c *fiber.Ctx) error {
 	return c.Redirect("/dashboard/users", fiber.StatusFound)
 }
 
-func renderUserFormError(title string, req any, message string, c *fiber.Ctx) error {
-	return renderer.Render(c, "dashboard/users/create", "layouts/dashboard", fiber.Map{
+func (h *UserHandler) renderUserFormError(c *fiber.Ctx, title string, req any, message string, fieldErrors map[string]string) error {
+	return renderer.Render(c, "dashboard/users/invite", "layouts/dashboard", h.withParentOptions(c, 0, h.withTenantOptions(c, fiber.Map{
 		"Title":                    title,
 		renderer.FlashErrorKeyView: message,
 		renderer.FormDataKey:       req,
-	}, http.StatusBadRequest)
+		"FieldErrors":              fieldErrors,
+	})), http.StatusBadRequest)
+}
+
+func (h *UserHandler) latestInvitations(c *fiber.Ctx, users []models.User) map[uint]*models.UserInvitation {
+	ids := make([]uint, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	invitations, err := h.invitationService.GetLatestInvitations(c.UserContext(), ids)
+	if err != nil {
+		logconfig.Log.Warn("Kullanıcı listesi: Davet durumları alınamadı", zap.Error(err))
+		return nil
+	}
+	return invitations
+}
+
+func (h *UserHandler) withParentOptions(c *fiber.Ctx, userID uint, data fiber.Map) fiber.Map {
+	candidates, err := h.hierarchyService.GetParentCandidates(c.UserContext(), userID)
+	if err != nil {
+		logconfig.Log.Warn("Kullanıcı formu: Üst kullanıcı seçenekleri alınamadı", zap.Error(err))
+		return data
+	}
+	data["ParentCandidates"] = candidates
+	return data
+}
+
+var userHistoryLabels = map[string]string{
+	"name":              "Ad Soyad",
+	"account":           "Hesap Adı",
+	"email":             "E-posta",
+	"email_verified_at": "E-posta Doğrulama",
+	"password":          "Şifre",
+	"status":            "Durum",
+	"type":              "Kullanıcı Tipi",
+	"is_super_admin":    "Süper Yönetici",
+	"parent_id":         "Üst Kullanıcı",
+	"tenant_id":         "Kiracı",
+}
+
+func (h *UserHandler) withHistory(c *fiber.Ctx, userID uint, data fiber.Map) fiber.Map {
+	entries, err := h.userService.GetUserHistory(c.UserContext(), userID)
+	if err != nil {
+		logconfig.Log.Warn("Kullanıcı formu: Değişiklik geçmişi alınamadı", zap.Uint("user_id", userID), zap.Error(err))
+		return data
+	}
+	data["History"] = entries
+	data["HistoryLabels"] = userHistoryLabels
+	return data
+}
+
+func optionalID(id uint) *uint {
+	if id == 0 {
+		return nil
+	}
+	return &id
+}
+
+func (h *UserHandler) withTenantOptions(c *fiber.Ctx, data fiber.Map) fiber.Map {
+	if isSuperAdmin, _ := c.Locals("isSuperAdmin").(bool); !isSuperAdmin {
+		return data
+	}
+	tenants, err := h.tenantService.GetAllTenants(c.UserContext())
+	if err != nil {
+		logconfig.Log.Warn("Kullanıcı formu: Kiracı listesi alınamadı", zap.Error(err))
+		return data
+	}
+	data["Tenants"] = tenants
+	return data
 }
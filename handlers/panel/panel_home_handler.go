@@ -2,14 +2,48 @@ package handlers
 
 import (
 	"net/http"
+	"zatrano/configs/logconfig"
 	"zatrano/pkg/renderer"
+	"zatrano/services"
 
 	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
 )
 
-func PanelHomeHandler(c *fiber.Ctx) error {
+type PanelHomeHandler struct {
+	hierarchyService services.IUserHierarchyService
+}
+
+func NewPanelHomeHandler() *PanelHomeHandler {
+	return &PanelHomeHandler{hierarchyService: services.NewUserHierarchyService()}
+}
+
+func (h *PanelHomeHandler) HomePage(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(uint)
+
+	team, err := h.hierarchyService.GetTeam(c.UserContext(), userID)
+	if err != nil {
+		logconfig.Log.Error("Aracı anasayfa: Ekip bilgisi alınamadı", zap.Uint("user_id", userID), zap.Error(err))
+	}
+
+	subordinateIDs, err := h.hierarchyService.GetSubordinateIDs(c.UserContext(), userID)
+	if err != nil {
+		logconfig.Log.Error("Aracı anasayfa: Alt kullanıcı sayısı alınamadı", zap.Uint("user_id", userID), zap.Error(err))
+	}
+
+	var activeCount int
+	for _, member := range team {
+		if member.Status {
+			activeCount++
+		}
+	}
+
 	mapData := fiber.Map{
-		"Title": "Aracı Ana Sayfa",
+		"Title":            "Aracı Ana Sayfa",
+		"Team":             team,
+		"DirectCount":      len(team),
+		"ActiveCount":      activeCount,
+		"TotalSubordinate": len(subordinateIDs),
 	}
 
 	return renderer.Render(c, "panel/home/home", "layouts/panel", mapData, http.StatusOK)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"zatrano/configs/sessionconfig"
 	"zatrano/pkg/flashmessages"
+	"zatrano/pkg/tenancy"
 	"zatrano/services"
 
 	"github.com/gofiber/fiber/v2"
@@ -23,21 +24,35 @@ func AuthMiddleware(c *fiber.Ctx) error {
 	}
 
 	authService := services.NewAuthService()
-	user, err := authService.GetUserProfile(userID)
+	user, err := authService.GetUserProfile(c.UserContext(), userID)
 	if err != nil {
 		_ = sess.Destroy()
 		_ = flashmessages.SetFlashMessage(c, flashmessages.FlashErrorKey, "Kullanıcı bulunamadı")
 		return c.Redirect("/auth/login")
 	}
 
-	ctx := context.WithValue(c.Context(), "user_id", userID)
+	ctx := context.WithValue(c.UserContext(), "user_id", userID)
 	ctx = context.WithValue(ctx, "user_type", user.Type)
 	ctx = context.WithValue(ctx, "user_account", user.Account)
+
+	tenantID, hasTenant := tenancy.TenantID(ctx)
+	if user.IsSuperAdmin {
+		if !hasTenant {
+			ctx = tenancy.WithBypass(ctx)
+		}
+	} else {
+		if hasTenant && tenantID != user.TenantID {
+			return c.Status(fiber.StatusForbidden).SendString("Bu kiracıya erişim yetkiniz yok")
+		}
+		ctx = tenancy.WithTenant(ctx, user.TenantID)
+		c.Locals("tenantID", user.TenantID)
+	}
 	c.SetUserContext(ctx)
 
 	c.Locals("userID", userID)
 	c.Locals("userType", user.Type)
 	c.Locals("userAccount", user.Account)
+	c.Locals("isSuperAdmin", user.IsSuperAdmin)
 
 	return c.Next()
 }
@@ -0,0 +1,25 @@
+package middlewares
+
+import (
+	"context"
+	"time"
+
+	"zatrano/configs/envconfig"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DeadlineMiddleware isteğin context'ine HTTP_REQUEST_TIMEOUT_SECONDS kadar süre tanır.
+// Süre dolduğunda bu context ile çalışan veritabanı sorguları iptal edilir.
+func DeadlineMiddleware() fiber.Handler {
+	timeout := time.Duration(envconfig.GetEnvAsInt("HTTP_REQUEST_TIMEOUT_SECONDS", 30)) * time.Second
+	return func(c *fiber.Ctx) error {
+		if timeout <= 0 {
+			return c.Next()
+		}
+		ctx, cancel := context.WithTimeout(c.UserContext(), timeout)
+		defer cancel()
+		c.SetUserContext(ctx)
+		return c.Next()
+	}
+}
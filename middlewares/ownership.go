@@ -0,0 +1,37 @@
+package middlewares
+
+import (
+	"zatrano/configs/logconfig"
+	"zatrano/models"
+	"zatrano/pkg/ownership"
+	"zatrano/services"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+func OwnershipMiddleware(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return c.Status(fiber.StatusUnauthorized).SendString("Oturum açılmamış")
+	}
+	userType, _ := c.Locals("userType").(models.UserType)
+
+	policy := ownership.Policy{
+		ActorID: userID,
+		Bypass:  userType == models.Dashboard,
+	}
+
+	if !policy.Bypass {
+		hierarchyService := services.NewUserHierarchyService()
+		subordinateIDs, err := hierarchyService.GetSubordinateIDs(c.UserContext(), userID)
+		if err != nil {
+			logconfig.Log.Warn("Sahiplik politikası: Alt kullanıcılar alınamadı, yalnızca kendi kayıtları gösterilecek",
+				zap.Uint("user_id", userID), zap.Error(err))
+		}
+		policy.SubordinateIDs = subordinateIDs
+	}
+
+	c.SetUserContext(ownership.WithPolicy(c.UserContext(), policy))
+	return c.Next()
+}
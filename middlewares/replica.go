@@ -0,0 +1,40 @@
+package middlewares
+
+import (
+	"strconv"
+	"time"
+
+	"zatrano/pkg/replica"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const readYourWritesCookie = "rw_until"
+
+// ReadYourWritesMiddleware yazma yapan bir isteğin ardından aynı istemcinin okumalarını,
+// okuma kopyaları yetişene kadar birincil veritabanına yönlendirir. Süre, istemciye
+// bırakılan bir çerezde tutulur; böylece oturum verisi bu amaçla yeniden yazılmaz.
+func ReadYourWritesMiddleware(c *fiber.Ctx) error {
+	if !replica.Enabled() {
+		return c.Next()
+	}
+
+	now := time.Now()
+	until, _ := strconv.ParseInt(c.Cookies(readYourWritesCookie), 10, 64)
+	ctx := replica.WithRequest(c.UserContext(), now.Unix() < until)
+	c.SetUserContext(ctx)
+
+	err := c.Next()
+
+	if replica.Wrote(ctx) {
+		expires := now.Add(replica.ReadYourWritesWindow())
+		c.Cookie(&fiber.Cookie{
+			Name:     readYourWritesCookie,
+			Value:    strconv.FormatInt(expires.Unix(), 10),
+			Expires:  expires,
+			HTTPOnly: true,
+			SameSite: fiber.CookieSameSiteLaxMode,
+		})
+	}
+	return err
+}
@@ -19,7 +19,7 @@ func StatusMiddleware(c *fiber.Ctx) error {
 	}
 
 	authService := services.NewAuthService()
-	user, err := authService.GetUserProfile(userID)
+	user, err := authService.GetUserProfile(c.UserContext(), userID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).SendString("Kullanıcı bulunamadı")
 	}
@@ -0,0 +1,68 @@
+package middlewares
+
+import (
+	"errors"
+	"strings"
+
+	"zatrano/configs/envconfig"
+	"zatrano/configs/sessionconfig"
+	"zatrano/pkg/tenancy"
+	"zatrano/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const tenantHeader = "X-Tenant"
+
+func TenantMiddleware(c *fiber.Ctx) error {
+	if slug := resolveTenantSlug(c); slug != "" {
+		tenantService := services.NewTenantService()
+		tenant, err := tenantService.ResolveTenant(c.UserContext(), slug)
+		if err != nil {
+			if errors.Is(err, services.ErrTenantInactive) {
+				return c.Status(fiber.StatusForbidden).SendString("Kiracı aktif değil")
+			}
+			return c.Status(fiber.StatusNotFound).SendString("Kiracı bulunamadı")
+		}
+
+		c.Locals("tenantID", tenant.ID)
+		c.SetUserContext(tenancy.WithTenant(c.UserContext(), tenant.ID))
+		return c.Next()
+	}
+
+	sess, err := sessionconfig.SessionStart(c)
+	if err != nil {
+		return c.Next()
+	}
+	if tenantID, err := sessionconfig.GetTenantIDFromSession(sess); err == nil {
+		c.Locals("tenantID", tenantID)
+		c.SetUserContext(tenancy.WithTenant(c.UserContext(), tenantID))
+	}
+
+	return c.Next()
+}
+
+func resolveTenantSlug(c *fiber.Ctx) string {
+	if slug := strings.TrimSpace(c.Get(tenantHeader)); slug != "" {
+		return slug
+	}
+
+	baseDomain := strings.ToLower(envconfig.GetEnvWithDefault("APP_BASE_DOMAIN", ""))
+	if baseDomain == "" {
+		return ""
+	}
+
+	host := strings.ToLower(c.Hostname())
+	if i := strings.IndexByte(host, ':'); i >= 0 {
+		host = host[:i]
+	}
+	if !strings.HasSuffix(host, "."+baseDomain) {
+		return ""
+	}
+
+	subdomain := strings.TrimSuffix(host, "."+baseDomain)
+	if subdomain == "" || subdomain == "www" || strings.Contains(subdomain, ".") {
+		return ""
+	}
+	return subdomain
+}
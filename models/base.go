@@ -25,6 +25,29 @@ type BaseModel struct {
 	DeletedBy *uint `gorm:"column:deleted_by"`
 }
 
+const VersionColumn = "version"
+
+// Versioned modeller iyimser kilitleme kullanır: her güncellemede sürüm bir artar ve
+// güncellemeyi gönderen taraf okuduğu sürümü ileterek araya giren değişiklikleri fark eder.
+type Versioned interface {
+	GetVersion() uint
+}
+
+// VersionModel, BaseModel'in yanına gömülerek modele sürüm sütunu ekler.
+type VersionModel struct {
+	Version uint `gorm:"not null;default:1"`
+}
+
+func (m *VersionModel) GetVersion() uint {
+	return m.Version
+}
+
+// Updatable modeller Update ile yazılabilecek sütunları bildirir; listede olmayan
+// sütunlar reddedilir. Uygulamayan modellerde Update hiçbir sütunu kabul etmez.
+type Updatable interface {
+	UpdatableColumns() []string
+}
+
 func (b *BaseModel) BeforeCreate(tx *gorm.DB) (err error) {
 	userID, ok := tx.Statement.Context.Value(contextUserIDKey).(uint)
 	if ok && userID != 0 {
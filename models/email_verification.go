@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+type EmailVerification struct {
+	BaseModel
+	UserID      uint      `gorm:"not null;index"`
+	Email       string    `gorm:"size:255;not null"`
+	TokenHash   string    `gorm:"size:64;not null;uniqueIndex" history:"mask"`
+	ExpiresAt   time.Time `gorm:"not null"`
+	ConfirmedAt *time.Time
+}
+
+func (EmailVerification) UpdatableColumns() []string {
+	return []string{"token_hash"}
+}
+
+func (v *EmailVerification) IsPending() bool {
+	return v.ConfirmedAt == nil && time.Now().Before(v.ExpiresAt)
+}
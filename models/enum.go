@@ -0,0 +1,42 @@
+package models
+
+import (
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// Enum PostgreSQL'de enum tipi olarak tutulan string türleridir. Değerler koddaki
+// sırayla veritabanına eklenir; migrasyon sırasında eksik değerler eklenir, başlangıçta
+// iki tarafın uyuştuğu doğrulanır. Yeni enum türleri Enums listesine eklenmelidir.
+type Enum interface {
+	EnumName() string
+	EnumValues() []string
+}
+
+// EnumRenamer yeniden adlandırılan enum değerlerini eski → yeni olarak bildirir;
+// değer silinip yeniden eklenmek yerine RENAME VALUE ile değiştirilir.
+type EnumRenamer interface {
+	EnumRenames() map[string]string
+}
+
+// Enums veritabanında tipi senkronize edilen enum türleridir.
+func Enums() []Enum {
+	return []Enum{UserType("")}
+}
+
+// enumVarcharMinSize enum sütunlarının PostgreSQL dışındaki en küçük genişliğidir.
+const enumVarcharMinSize = 10
+
+// EnumDBDataType GormDBDataType için ortak gövdedir: PostgreSQL'de enum tipinin adını,
+// diğer sürücülerde en uzun değeri alacak genişlikte varchar döner.
+func EnumDBDataType(db *gorm.DB, e Enum) string {
+	if db.Dialector.Name() == "postgres" {
+		return e.EnumName()
+	}
+	size := enumVarcharMinSize
+	for _, v := range e.EnumValues() {
+		size = max(size, len(v))
+	}
+	return "varchar(" + strconv.Itoa(size) + ")"
+}
@@ -0,0 +1,138 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+type HistoryAction string
+
+const (
+	HistoryCreate  HistoryAction = "create"
+	HistoryUpdate  HistoryAction = "update"
+	HistoryDelete  HistoryAction = "delete"
+	HistoryRestore HistoryAction = "restore"
+)
+
+func (a HistoryAction) Label() string {
+	switch a {
+	case HistoryCreate:
+		return "Oluşturuldu"
+	case HistoryUpdate:
+		return "Güncellendi"
+	case HistoryDelete:
+		return "Silindi"
+	case HistoryRestore:
+		return "Geri yüklendi"
+	default:
+		return string(a)
+	}
+}
+
+// Alanlara eklenen `history:"mask"` etiketi değerin geçmişte gizlenmesini,
+// `history:"-"` ise alanın hiç kaydedilmemesini sağlar.
+const (
+	HistoryTag      = "history"
+	HistoryTagMask  = "mask"
+	HistoryTagSkip  = "-"
+	HistoryMaskText = "********"
+)
+
+// HistoryTracked, BaseModel'i gömen modelleri işaretler; bu modellerdeki her
+// oluşturma, güncelleme, silme ve geri yükleme EntityHistory'ye yazılır.
+type HistoryTracked interface {
+	historyTracked()
+}
+
+func (*BaseModel) historyTracked() {}
+
+type EntityHistory struct {
+	ID         uint           `gorm:"primarykey"`
+	EntityType string         `gorm:"size:100;not null;index:idx_entity_histories_entity,priority:1"`
+	EntityID   uint           `gorm:"not null;index:idx_entity_histories_entity,priority:2"`
+	Action     HistoryAction  `gorm:"size:20;not null"`
+	ActorID    *uint          `gorm:"index"`
+	Changes    HistoryChanges `gorm:"not null"`
+	CreatedAt  time.Time      `gorm:"index"`
+
+	// ActorName yalnızca geçmiş listelenirken kullanıcı tablosundan doldurulur.
+	ActorName string `gorm:"->;-:migration"`
+}
+
+// FieldChange bir alanın işlem öncesi ve sonrası JSON değeridir; oluşturmada
+// Before, silmede After boştur.
+type FieldChange struct {
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+func (c FieldChange) BeforeText() string {
+	return changeText(c.Before)
+}
+
+func (c FieldChange) AfterText() string {
+	return changeText(c.After)
+}
+
+func changeText(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "—"
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var b bool
+	if err := json.Unmarshal(raw, &b); err == nil {
+		if b {
+			return "Evet"
+		}
+		return "Hayır"
+	}
+	return string(raw)
+}
+
+// HistoryChanges sütun adına göre alan değişiklikleridir.
+type HistoryChanges map[string]FieldChange
+
+func (HistoryChanges) GormDataType() string {
+	return "json"
+}
+
+func (HistoryChanges) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	switch db.Dialector.Name() {
+	case "postgres":
+		return "jsonb"
+	case "mysql":
+		return "json"
+	default:
+		return "text"
+	}
+}
+
+func (c HistoryChanges) Value() (driver.Value, error) {
+	if c == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(c)
+	return string(b), err
+}
+
+func (c *HistoryChanges) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*c = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	default:
+		return fmt.Errorf("HistoryChanges: desteklenmeyen tür %T", value)
+	}
+}
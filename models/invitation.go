@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+type InvitationStatus string
+
+const (
+	InvitationPending  InvitationStatus = "pending"
+	InvitationAccepted InvitationStatus = "accepted"
+	InvitationRevoked  InvitationStatus = "revoked"
+	InvitationExpired  InvitationStatus = "expired"
+)
+
+type UserInvitation struct {
+	BaseModel
+	UserID     uint      `gorm:"not null;index"`
+	User       *User     `gorm:"foreignKey:UserID"`
+	Email      string    `gorm:"size:255;not null"`
+	TokenHash  string    `gorm:"size:64;not null;uniqueIndex" history:"mask"`
+	ExpiresAt  time.Time `gorm:"not null"`
+	AcceptedAt *time.Time
+	RevokedAt  *time.Time
+	SentCount  int `gorm:"not null;default:0"`
+	LastSentAt *time.Time
+}
+
+func (UserInvitation) UpdatableColumns() []string {
+	return []string{"token_hash", "expires_at", "sent_count", "last_sent_at", "revoked_at"}
+}
+
+func (i *UserInvitation) Status() InvitationStatus {
+	switch {
+	case i.AcceptedAt != nil:
+		return InvitationAccepted
+	case i.RevokedAt != nil:
+		return InvitationRevoked
+	case time.Now().After(i.ExpiresAt):
+		return InvitationExpired
+	default:
+		return InvitationPending
+	}
+}
+
+func (i *UserInvitation) IsPending() bool {
+	return i.Status() == InvitationPending
+}
@@ -0,0 +1,13 @@
+package models
+
+import "gorm.io/gorm/clause"
+
+type OwnerScoped interface {
+	OwnerColumn() string
+}
+
+// OwnerFiltered sahipliği tek bir sütunla ifade edilemeyen modeller içindir; koşulu
+// politikanın sahip kimliklerinden (kullanıcı ve alt kullanıcıları) model kurar.
+type OwnerFiltered interface {
+	OwnerCondition(ownerIDs []uint) clause.Expression
+}
@@ -0,0 +1,9 @@
+package models
+
+import "zatrano/pkg/fulltext"
+
+// FullTextSearchable modelin tam metin aramasına dahil edilen sütunlarını bildirir;
+// migrasyon bu alanlardan search_vector sütununu ve GIN indeksini oluşturur.
+type FullTextSearchable interface {
+	SearchFields() []fulltext.Field
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// SeederRun çalıştırılmış seeder'ların kaydıdır; tekrarlanabilir olmayan seeder'lar
+// bir kez çalıştıktan sonra atlanır.
+type SeederRun struct {
+	Name  string    `gorm:"primaryKey;size:100"`
+	Env   string    `gorm:"size:10;not null"`
+	Count int       `gorm:"not null;default:0"`
+	RanAt time.Time `gorm:"not null"`
+}
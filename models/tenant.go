@@ -0,0 +1,27 @@
+package models
+
+type Tenant struct {
+	BaseModel
+	Name   string `gorm:"size:100;not null"`
+	Slug   string `gorm:"size:63;unique;not null"`
+	Status bool   `gorm:"default:true;index"`
+}
+
+const TenantColumn = "tenant_id"
+
+type TenantScoped interface {
+	GetTenantID() uint
+	SetTenantID(id uint)
+}
+
+type TenantModel struct {
+	TenantID uint `gorm:"not null;default:0;index"`
+}
+
+func (m *TenantModel) GetTenantID() uint {
+	return m.TenantID
+}
+
+func (m *TenantModel) SetTenantID(id uint) {
+	m.TenantID = id
+}
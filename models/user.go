@@ -1,8 +1,15 @@
 package models
 
 import (
+	"strings"
+	"time"
+
+	"zatrano/pkg/fulltext"
+	"zatrano/pkg/turkishsearch"
+
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/schema"
 )
 
@@ -13,23 +20,96 @@ const (
 	Panel     UserType = "panel"
 )
 
-func (UserType) GormDataType() string {
+func (UserType) EnumName() string {
 	return "user_type"
 }
-func (UserType) GormDBDataType(db *gorm.DB, field *schema.Field) string {
-	if db.Dialector.Name() == "postgres" {
-		return "user_type"
-	}
-	return "varchar(10)"
+
+func (UserType) EnumValues() []string {
+	return []string{string(Dashboard), string(Panel)}
+}
+
+func (t UserType) GormDataType() string {
+	return t.EnumName()
+}
+func (t UserType) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	return EnumDBDataType(db, t)
 }
 
 type User struct {
 	BaseModel
-	Name     string   `gorm:"size:100;not null;index"`
-	Account  string   `gorm:"size:100;unique;not null"`
-	Password string   `gorm:"size:255;not null"`
-	Status   bool     `gorm:"default:true;index"`
-	Type     UserType `gorm:"type:user_type;not null;default:'panel';index"`
+	TenantModel
+	VersionModel
+	Name            string  `gorm:"size:100;not null;index"`
+	Account         string  `gorm:"size:100;not null"`
+	AccountKey      string  `gorm:"size:100;not null;uniqueIndex" history:"-"`
+	Email           *string `gorm:"size:255;uniqueIndex"`
+	EmailVerifiedAt *time.Time
+	Password        string   `gorm:"size:255;not null" history:"mask"`
+	Status          bool     `gorm:"default:true;index"`
+	Type            UserType `gorm:"type:user_type;not null;default:'panel';index"`
+	IsSuperAdmin    bool     `gorm:"default:false"`
+	ParentID        *uint    `gorm:"index"`
+	Parent          *User    `gorm:"foreignKey:ParentID"`
+	Children        []User   `gorm:"foreignKey:ParentID"`
+
+	// SearchHeadline yalnızca tam metin aramasında doldurulan vurgulu özettir.
+	SearchHeadline string `gorm:"->;-:migration" json:"search_headline,omitempty"`
+}
+
+// UserPatch yönetim panelinden değiştirilebilen kullanıcı alanlarıdır; nil alanlar
+// olduğu gibi kalır. Version verilirse güncelleme okunan sürüme göre yapılır.
+type UserPatch struct {
+	Name       *string
+	Account    *string
+	AccountKey *string
+	Status     *bool
+	Type       *UserType
+	Version    *uint
+}
+
+// UpdatableColumns şifre, e-posta ve hiyerarşi dışındaki alanlardır; onlar kendi
+// akışlarındaki depo metotlarıyla değiştirilir.
+func (User) UpdatableColumns() []string {
+	return []string{"name", "account", "account_key", "status", "type"}
+}
+
+func (User) SearchFields() []fulltext.Field {
+	return []fulltext.Field{
+		{Column: "name", Weight: "A"},
+		{Column: "account", Weight: "A"},
+		{Column: "email", Weight: "B"},
+	}
+}
+
+// AccountKey hesap adının benzersizlik kontrolünde kullanılan katlanmış halidir;
+// "Ali@x" ile "ali@x" aynı anahtara düşer.
+func AccountKey(account string) string {
+	return turkishsearch.Fold(strings.TrimSpace(account))
+}
+
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func (u *User) EmailAddress() string {
+	if u.Email == nil {
+		return ""
+	}
+	return *u.Email
+}
+
+func (u *User) IsEmailVerified() bool {
+	return u.Email != nil && u.EmailVerifiedAt != nil
+}
+
+// OwnerCondition kullanıcı kayıtlarını oluşturana göre değil kimliğe göre süzer;
+// panel kullanıcısı kendisini ve hiyerarşideki ekibini görür.
+func (User) OwnerCondition(ownerIDs []uint) clause.Expression {
+	values := make([]interface{}, len(ownerIDs))
+	for i, id := range ownerIDs {
+		values[i] = id
+	}
+	return clause.IN{Column: clause.Column{Table: clause.CurrentTable, Name: "id"}, Values: values}
 }
 
 func (u *User) CheckPassword(password string) error {
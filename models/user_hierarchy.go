@@ -0,0 +1,21 @@
+package models
+
+type UserHierarchy struct {
+	AncestorID   uint `gorm:"primaryKey;autoIncrement:false"`
+	DescendantID uint `gorm:"primaryKey;autoIncrement:false;index"`
+	Depth        int  `gorm:"not null;index"`
+}
+
+type TeamMember struct {
+	ID       uint
+	Name     string
+	Account  string
+	Status   bool
+	Type     UserType
+	TeamSize int64
+}
+
+type UserTreeNode struct {
+	User     User
+	Children []*UserTreeNode
+}
@@ -0,0 +1,187 @@
+package apperrors
+
+import (
+	"errors"
+	"net/http"
+)
+
+type Kind string
+
+const (
+	KindNotFound     Kind = "not_found"
+	KindConflict     Kind = "conflict"
+	KindValidation   Kind = "validation"
+	KindUnauthorized Kind = "unauthorized"
+	KindForbidden    Kind = "forbidden"
+	KindTimeout      Kind = "timeout"
+	KindInternal     Kind = "internal"
+)
+
+// Error katmanlar arasında taşınan tipli hatadır. Message kullanıcıya gösterilebilecek
+// yerelleştirilmiş metindir; Err ise yalnızca loglarda görünen asıl nedendir.
+type Error struct {
+	Kind    Kind
+	Message string
+	Fields  map[string]string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is, WithCause ile kopyalanmış bir hatanın errors.Is ile kendi sentinel'ine
+// eşit sayılmasını sağlar.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && e.Kind == t.Kind && e.Message == t.Message
+}
+
+func New(kind Kind, message string) *Error {
+	return &Error{Kind: kind, Message: message}
+}
+
+func NotFound(message string) *Error {
+	return New(KindNotFound, message)
+}
+
+// Conflict benzersizlik gibi mevcut durumla çakışan işlemler içindir; verilen alanlar
+// formda hatanın gösterileceği alanlardır.
+func Conflict(message string, fields ...string) *Error {
+	return withFields(New(KindConflict, message), fields)
+}
+
+func Validation(message string, fields ...string) *Error {
+	return withFields(New(KindValidation, message), fields)
+}
+
+func Unauthorized(message string) *Error {
+	return New(KindUnauthorized, message)
+}
+
+func Forbidden(message string) *Error {
+	return New(KindForbidden, message)
+}
+
+func Internal(message string, err error) *Error {
+	return &Error{Kind: KindInternal, Message: message, Err: err}
+}
+
+// Wrap, alt katmanda belirlenmiş anlamlı bir türü (ör. Conflict, NotFound) korur;
+// tipsiz ya da Internal hataları verilen tür ve mesajla sarar.
+func Wrap(err error, kind Kind, message string) error {
+	if err == nil {
+		return nil
+	}
+	if appErr, ok := As(err); ok && appErr.Kind != KindInternal {
+		return err
+	}
+	return &Error{Kind: kind, Message: message, Err: err}
+}
+
+// WithCause sentinel bir hatayı asıl nedeni de taşıyacak şekilde kopyalar;
+// errors.Is(sonuç, sentinel) doğru kalır.
+func (e *Error) WithCause(err error) *Error {
+	c := *e
+	c.Err = err
+	return &c
+}
+
+func withFields(e *Error, fields []string) *Error {
+	if len(fields) == 0 {
+		return e
+	}
+	e.Fields = make(map[string]string, len(fields))
+	for _, f := range fields {
+		e.Fields[f] = e.Message
+	}
+	return e
+}
+
+func As(err error) (*Error, bool) {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
+}
+
+func KindOf(err error) Kind {
+	if err == nil {
+		return ""
+	}
+	if appErr, ok := As(err); ok {
+		return appErr.Kind
+	}
+	return KindInternal
+}
+
+func IsKind(err error, kind Kind) bool {
+	return err != nil && KindOf(err) == kind
+}
+
+func Fields(err error) map[string]string {
+	if appErr, ok := As(err); ok {
+		return appErr.Fields
+	}
+	return nil
+}
+
+func (k Kind) HTTPStatus() int {
+	switch k {
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindValidation:
+		return http.StatusUnprocessableEntity
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindTimeout:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (k Kind) DefaultMessage() string {
+	switch k {
+	case KindNotFound:
+		return "Kayıt bulunamadı."
+	case KindConflict:
+		return "Kayıt mevcut verilerle çakışıyor."
+	case KindValidation:
+		return "Gönderilen bilgiler geçersiz."
+	case KindUnauthorized:
+		return "Bu işlem için giriş yapmalısınız."
+	case KindForbidden:
+		return "Bu işlem için yetkiniz yok."
+	case KindTimeout:
+		return "İşlem zaman aşımına uğradı. Lütfen tekrar deneyin."
+	default:
+		return "İşlem sırasında bir sorun oluştu. Lütfen tekrar deneyin."
+	}
+}
+
+func HTTPStatus(err error) int {
+	return KindOf(err).HTTPStatus()
+}
+
+// Message hatanın kullanıcıya gösterilecek metnini döner. İç hatalarda ayrıntılar
+// sızdırılmaz, türün varsayılan mesajı kullanılır.
+func Message(err error) string {
+	appErr, ok := As(err)
+	if !ok || appErr.Kind == KindInternal || appErr.Message == "" {
+		return KindOf(err).DefaultMessage()
+	}
+	return appErr.Message
+}
@@ -0,0 +1,55 @@
+package exporter
+
+type Column[T any] struct {
+	Key    string
+	Header string
+	Value  func(item *T, locale string) interface{}
+}
+
+func SelectColumns[T any](available []Column[T], keys []string) ([]Column[T], error) {
+	if len(keys) == 0 {
+		return available, nil
+	}
+
+	index := make(map[string]Column[T], len(available))
+	for _, col := range available {
+		index[col.Key] = col
+	}
+
+	selected := make([]Column[T], 0, len(keys))
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		col, ok := index[key]
+		if !ok {
+			return nil, ErrUnknownColumn
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		selected = append(selected, col)
+	}
+
+	if len(selected) == 0 {
+		return nil, ErrNoColumns
+	}
+	return selected, nil
+}
+
+func Headers[T any](columns []Column[T]) ([]string, []string) {
+	keys := make([]string, len(columns))
+	headers := make([]string, len(columns))
+	for i, col := range columns {
+		keys[i] = col.Key
+		headers[i] = col.Header
+	}
+	return keys, headers
+}
+
+func Values[T any](columns []Column[T], item *T, locale string) []interface{} {
+	values := make([]interface{}, len(columns))
+	for i, col := range columns {
+		values[i] = col.Value(item, locale)
+	}
+	return values
+}
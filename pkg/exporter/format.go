@@ -0,0 +1,118 @@
+package exporter
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+	FormatJSON Format = "json"
+)
+
+const (
+	LocaleTR      = "tr"
+	LocaleEN      = "en"
+	DefaultLocale = LocaleTR
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("desteklenmeyen dışa aktarma formatı")
+	ErrUnknownColumn     = errors.New("bilinmeyen dışa aktarma sütunu")
+	ErrNoColumns         = errors.New("dışa aktarılacak sütun seçilmedi")
+)
+
+type Options struct {
+	Format  Format
+	Columns []string
+	Locale  string
+}
+
+func ParseFormat(value string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(value))) {
+	case FormatCSV, "":
+		return FormatCSV, nil
+	case FormatXLSX:
+		return FormatXLSX, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+func ParseOptions(format, columns, locale string) (Options, error) {
+	f, err := ParseFormat(format)
+	if err != nil {
+		return Options{}, err
+	}
+
+	var cols []string
+	for _, col := range strings.Split(columns, ",") {
+		col = strings.TrimSpace(col)
+		if col != "" {
+			cols = append(cols, col)
+		}
+	}
+
+	return Options{Format: f, Columns: cols, Locale: NormalizeLocale(locale)}, nil
+}
+
+func NormalizeLocale(locale string) string {
+	switch strings.ToLower(strings.TrimSpace(locale)) {
+	case LocaleEN:
+		return LocaleEN
+	default:
+		return DefaultLocale
+	}
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatJSON:
+		return "application/json; charset=utf-8"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+func (f Format) Extension() string {
+	return string(f)
+}
+
+func FileName(base string, f Format) string {
+	return base + "_" + time.Now().Format("20060102_150405") + "." + f.Extension()
+}
+
+func FormatTime(t time.Time, locale string) string {
+	if t.IsZero() {
+		return ""
+	}
+	switch locale {
+	case LocaleEN:
+		return t.Format("2006-01-02 15:04")
+	default:
+		return t.Format("02.01.2006 15:04")
+	}
+}
+
+func FormatBool(b bool, locale string) string {
+	switch locale {
+	case LocaleEN:
+		if b {
+			return "Yes"
+		}
+		return "No"
+	default:
+		if b {
+			return "Evet"
+		}
+		return "Hayır"
+	}
+}
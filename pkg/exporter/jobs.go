@@ -0,0 +1,290 @@
+package exporter
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"zatrano/configs/envconfig"
+	"zatrano/configs/logconfig"
+
+	"go.uber.org/zap"
+)
+
+type JobStatus string
+
+const (
+	JobPending   JobStatus = "pending"
+	JobRunning   JobStatus = "running"
+	JobCompleted JobStatus = "completed"
+	JobFailed    JobStatus = "failed"
+)
+
+const (
+	jobRetention  = 24 * time.Hour
+	jobIDBytes    = 16
+	jobStateExt   = ".json"
+	jobPartialExt = ".part"
+)
+
+var ErrJobsNotInitialized = errors.New("dışa aktarma iş yöneticisi başlatılmamış")
+
+type Job struct {
+	ID         string    `json:"id"`
+	OwnerID    uint      `json:"owner_id"`
+	FileName   string    `json:"file_name"`
+	Format     Format    `json:"format"`
+	Status     JobStatus `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	Host       string    `json:"host"`
+	CreatedAt  time.Time `json:"created_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	path       string
+}
+
+func (j Job) Path() string {
+	return j.path
+}
+
+func (j Job) IsReady() bool {
+	return j.Status == JobCompleted
+}
+
+// JobManager iş durumlarını çıktı dosyalarının yanında <id>.json olarak saklar; işler
+// yeniden başlatmadan sonra da görünür. Birden çok sunucu aynı işleri görecekse
+// dışa aktarma klasörü paylaşılan bir diskte olmalıdır.
+type JobManager struct {
+	dir  string
+	host string
+}
+
+var Jobs *JobManager
+
+func InitJobs(dir string) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		panic("Dışa aktarma klasörü oluşturulamadı: " + dir + " | Hata: " + err.Error())
+	}
+	host, _ := os.Hostname()
+	Jobs = &JobManager{dir: dir, host: host}
+	Jobs.failInterrupted()
+	logconfig.SLog.Infow("Dışa aktarma iş yöneticisi başlatıldı", "dir", dir)
+}
+
+func AsyncThreshold() int64 {
+	return int64(envconfig.GetEnvAsInt("EXPORT_ASYNC_THRESHOLD", 5000))
+}
+
+func (m *JobManager) Start(ownerID uint, fileName string, format Format, run func(w io.Writer) error) (*Job, error) {
+	if m == nil {
+		return nil, ErrJobsNotInitialized
+	}
+
+	id, err := newJobID()
+	if err != nil {
+		return nil, err
+	}
+
+	job := &Job{
+		ID:        id,
+		OwnerID:   ownerID,
+		FileName:  fileName,
+		Format:    format,
+		Status:    JobPending,
+		Host:      m.host,
+		CreatedAt: time.Now(),
+	}
+	job.path = m.outputPath(job)
+
+	m.prune()
+	if err := m.save(job); err != nil {
+		return nil, err
+	}
+
+	// Durum arka planda değiştiği için çağırana kopyası verilir.
+	started := *job
+	go m.run(job, run)
+	return &started, nil
+}
+
+func (m *JobManager) run(job *Job, run func(w io.Writer) error) {
+	m.setStatus(job, JobRunning, "")
+
+	// Çıktı önce geçici dosyaya yazılır; yarım kalan dosya hiçbir zaman indirilemez.
+	partial := job.path + jobPartialExt
+	err := func() error {
+		file, err := os.Create(partial)
+		if err != nil {
+			return err
+		}
+		if err := run(file); err != nil {
+			_ = file.Close()
+			return err
+		}
+		if err := file.Close(); err != nil {
+			return err
+		}
+		return os.Rename(partial, job.path)
+	}()
+
+	if err != nil {
+		logconfig.Log.Error("Dışa aktarma işi başarısız oldu",
+			zap.String("job_id", job.ID),
+			zap.Uint("owner_id", job.OwnerID),
+			zap.Error(err),
+		)
+		_ = os.Remove(partial)
+		m.setStatus(job, JobFailed, err.Error())
+		return
+	}
+
+	logconfig.Log.Info("Dışa aktarma işi tamamlandı",
+		zap.String("job_id", job.ID),
+		zap.Uint("owner_id", job.OwnerID),
+		zap.String("file", job.FileName),
+	)
+	m.setStatus(job, JobCompleted, "")
+}
+
+func (m *JobManager) setStatus(job *Job, status JobStatus, errMsg string) {
+	job.Status = status
+	job.Error = errMsg
+	if status == JobCompleted || status == JobFailed {
+		job.FinishedAt = time.Now()
+	}
+	if err := m.save(job); err != nil {
+		logconfig.Log.Error("Dışa aktarma işinin durumu kaydedilemedi", zap.String("job_id", job.ID), zap.Error(err))
+	}
+}
+
+func (m *JobManager) Get(id string) (Job, bool) {
+	if m == nil || !isJobID(id) {
+		return Job{}, false
+	}
+	job, err := m.load(m.statePath(id))
+	if err != nil {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+func (m *JobManager) ListByOwner(ownerID uint) []Job {
+	if m == nil {
+		return nil
+	}
+
+	var jobs []Job
+	for _, job := range m.all() {
+		if job.OwnerID == ownerID {
+			jobs = append(jobs, *job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.After(jobs[j].CreatedAt)
+	})
+	return jobs
+}
+
+// failInterrupted bu sunucuda çalışırken uygulama kapandığı için yarım kalan işleri
+// başarısız olarak işaretler.
+func (m *JobManager) failInterrupted() {
+	for _, job := range m.all() {
+		if job.Host != m.host || (job.Status != JobPending && job.Status != JobRunning) {
+			continue
+		}
+		_ = os.Remove(job.path + jobPartialExt)
+		m.setStatus(job, JobFailed, "uygulama yeniden başlatıldığı için iş yarım kaldı")
+		logconfig.Log.Warn("Yarım kalan dışa aktarma işi başarısız olarak işaretlendi", zap.String("job_id", job.ID))
+	}
+}
+
+// prune saklama süresini aşan işleri dosyalarıyla birlikte siler. Başka bir sunucu
+// kapandığı için hiç bitmeyen işler de bu sürenin sonunda temizlenir.
+func (m *JobManager) prune() {
+	cutoff := time.Now().Add(-jobRetention)
+	for _, job := range m.all() {
+		if job.CreatedAt.Before(cutoff) {
+			_ = os.Remove(job.path)
+			_ = os.Remove(job.path + jobPartialExt)
+			_ = os.Remove(m.statePath(job.ID))
+		}
+	}
+}
+
+func (m *JobManager) all() []*Job {
+	paths, err := filepath.Glob(filepath.Join(m.dir, "*"+jobStateExt))
+	if err != nil {
+		return nil
+	}
+	jobs := make([]*Job, 0, len(paths))
+	for _, path := range paths {
+		if !isJobID(strings.TrimSuffix(filepath.Base(path), jobStateExt)) {
+			continue
+		}
+		job, err := m.load(path)
+		if err != nil {
+			logconfig.Log.Warn("Dışa aktarma işi okunamadı", zap.String("path", path), zap.Error(err))
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs
+}
+
+func (m *JobManager) load(path string) (*Job, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, err
+	}
+	job.path = m.outputPath(&job)
+	return &job, nil
+}
+
+// save durumu önce geçici dosyaya yazıp yerine taşır; okuyan taraf yarım JSON görmez.
+func (m *JobManager) save(job *Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	path := m.statePath(job.ID)
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+func (m *JobManager) statePath(id string) string {
+	return filepath.Join(m.dir, id+jobStateExt)
+}
+
+func (m *JobManager) outputPath(job *Job) string {
+	return filepath.Join(m.dir, job.ID+"."+job.Format.Extension())
+}
+
+// isJobID kimliğin newJobID biçiminde olduğunu doğrular; istekten gelen kimlik dosya
+// yoluna eklendiği için klasör dışına çıkılması engellenir.
+func isJobID(id string) bool {
+	if len(id) != hex.EncodedLen(jobIDBytes) {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil
+}
+
+func newJobID() (string, error) {
+	b := make([]byte, jobIDBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,190 @@
+package exporter
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type Writer interface {
+	WriteRow(values []interface{}) error
+	Close() error
+}
+
+func NewWriter(format Format, w io.Writer, keys []string, headers []string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, headers)
+	case FormatXLSX:
+		return newXLSXWriter(w, headers)
+	case FormatJSON:
+		return newJSONWriter(w, keys)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer, headers []string) (*csvWriter, error) {
+	// Excel'in UTF-8 karakterleri doğru göstermesi için BOM yazılır.
+	if _, err := w.Write([]byte("\xEF\xBB\xBF")); err != nil {
+		return nil, err
+	}
+	cw := &csvWriter{w: csv.NewWriter(w)}
+	if err := cw.w.Write(headers); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (cw *csvWriter) WriteRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		switch v := v.(type) {
+		case nil:
+		case string:
+			record[i] = escapeFormula(v)
+		default:
+			record[i] = fmt.Sprint(v)
+		}
+	}
+	return cw.w.Write(record)
+}
+
+func (cw *csvWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+type jsonWriter struct {
+	w     *bufio.Writer
+	keys  [][]byte
+	first bool
+}
+
+func newJSONWriter(w io.Writer, keys []string) (*jsonWriter, error) {
+	jw := &jsonWriter{w: bufio.NewWriter(w), first: true}
+	for _, key := range keys {
+		encoded, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		jw.keys = append(jw.keys, encoded)
+	}
+	if _, err := jw.w.WriteString("["); err != nil {
+		return nil, err
+	}
+	return jw, nil
+}
+
+func (jw *jsonWriter) WriteRow(values []interface{}) error {
+	if !jw.first {
+		if err := jw.w.WriteByte(','); err != nil {
+			return err
+		}
+	}
+	jw.first = false
+
+	if err := jw.w.WriteByte('{'); err != nil {
+		return err
+	}
+	for i, v := range values {
+		if i > 0 {
+			if err := jw.w.WriteByte(','); err != nil {
+				return err
+			}
+		}
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if _, err := jw.w.Write(jw.keys[i]); err != nil {
+			return err
+		}
+		if err := jw.w.WriteByte(':'); err != nil {
+			return err
+		}
+		if _, err := jw.w.Write(encoded); err != nil {
+			return err
+		}
+	}
+	return jw.w.WriteByte('}')
+}
+
+func (jw *jsonWriter) Close() error {
+	if _, err := jw.w.WriteString("]"); err != nil {
+		return err
+	}
+	return jw.w.Flush()
+}
+
+const xlsxSheetName = "Sheet1"
+
+type xlsxWriter struct {
+	out    io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXLSXWriter(w io.Writer, headers []string) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+	stream, err := file.NewStreamWriter(xlsxSheetName)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	xw := &xlsxWriter{out: w, file: file, stream: stream, row: 1}
+	headerRow := make([]interface{}, len(headers))
+	for i, h := range headers {
+		headerRow[i] = h
+	}
+	if err := xw.WriteRow(headerRow); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return xw, nil
+}
+
+func (xw *xlsxWriter) WriteRow(values []interface{}) error {
+	cell, err := excelize.CoordinatesToCellName(1, xw.row)
+	if err != nil {
+		return err
+	}
+	xw.row++
+	escaped := make([]interface{}, len(values))
+	for i, v := range values {
+		if s, ok := v.(string); ok {
+			v = escapeFormula(s)
+		}
+		escaped[i] = v
+	}
+	return xw.stream.SetRow(cell, escaped)
+}
+
+// escapeFormula =, +, -, @ ile başlayan metinlerin başına ' ekler; kullanıcıdan gelen
+// ad ve hesapların dosya Excel'de açıldığında formül olarak çalışmasını engeller.
+func escapeFormula(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func (xw *xlsxWriter) Close() error {
+	defer xw.file.Close()
+	if err := xw.stream.Flush(); err != nil {
+		return err
+	}
+	_, err := xw.file.WriteTo(xw.out)
+	return err
+}
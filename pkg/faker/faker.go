@@ -0,0 +1,98 @@
+package faker
+
+import (
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"zatrano/pkg/turkishsearch"
+)
+
+var firstNames = []string{
+	"Ahmet", "Mehmet", "Mustafa", "Ali", "Hüseyin", "Hasan", "İbrahim", "İsmail", "Osman", "Yusuf",
+	"Murat", "Ömer", "Ramazan", "Halil", "Süleyman", "Abdullah", "Mahmut", "Recep", "Salih", "Fatih",
+	"Emre", "Burak", "Can", "Cem", "Deniz", "Efe", "Eren", "Kaan", "Kerem", "Onur",
+	"Oğuz", "Serkan", "Tolga", "Uğur", "Volkan", "Yasin", "Barış", "Çağlar", "Gökhan", "Şahin",
+	"Fatma", "Ayşe", "Emine", "Hatice", "Zeynep", "Elif", "Meryem", "Şerife", "Sultan", "Zehra",
+	"Hanife", "Merve", "Özlem", "Esra", "Büşra", "Ebru", "Derya", "Gül", "Selin", "Sibel",
+	"Ceren", "Damla", "Ece", "Gizem", "İrem", "Melike", "Nur", "Pınar", "Seda", "Tuğba",
+	"Yağmur", "Çiğdem", "Gülşen", "Işıl", "Dilek", "Aslı", "Burcu", "Canan", "Duygu", "Hülya",
+}
+
+var lastNames = []string{
+	"Yılmaz", "Kaya", "Demir", "Şahin", "Çelik", "Yıldız", "Yıldırım", "Öztürk", "Aydın", "Özdemir",
+	"Arslan", "Doğan", "Kılıç", "Aslan", "Çetin", "Kara", "Koç", "Kurt", "Özkan", "Şimşek",
+	"Polat", "Özcan", "Korkmaz", "Çakır", "Erdoğan", "Yavuz", "Can", "Acar", "Şen", "Aktaş",
+	"Güler", "Yalçın", "Güneş", "Bozkurt", "Bulut", "Keskin", "Ünal", "Turan", "Gül", "Özer",
+	"Işık", "Kaplan", "Avcı", "Sarı", "Tekin", "Taş", "Köse", "Yüksel", "Ateş", "Aksoy",
+}
+
+var cities = []string{
+	"İstanbul", "Ankara", "İzmir", "Bursa", "Antalya", "Konya", "Adana", "Şanlıurfa", "Gaziantep", "Kocaeli",
+	"Mersin", "Diyarbakır", "Hatay", "Manisa", "Kayseri", "Samsun", "Balıkesir", "Kahramanmaraş", "Van", "Aydın",
+	"Denizli", "Sakarya", "Tekirdağ", "Muğla", "Eskişehir", "Mardin", "Trabzon", "Malatya", "Erzurum", "Çanakkale",
+}
+
+var emailDomains = []string{"example.com", "example.org", "example.net"}
+
+// Faker Türkçe örnek veriler üretir. Aynı tohumla oluşturulan iki Faker aynı diziyi
+// üretir; tekrarlanabilir test verisi için tohum sabitlenebilir.
+type Faker struct {
+	r *rand.Rand
+}
+
+// New tohum 0 ise zamana göre rastgele bir tohumla başlar.
+func New(seed uint64) *Faker {
+	if seed == 0 {
+		seed = uint64(time.Now().UnixNano())
+	}
+	return &Faker{r: rand.New(rand.NewPCG(seed, seed>>1|1))}
+}
+
+func (f *Faker) Pick(values []string) string {
+	return values[f.r.IntN(len(values))]
+}
+
+// IntBetween min ile max (dahil) arasında bir sayı döner.
+func (f *Faker) IntBetween(min, max int) int {
+	return min + f.r.IntN(max-min+1)
+}
+
+// Chance verilen olasılıkla true döner (0-1 arası).
+func (f *Faker) Chance(probability float64) bool {
+	return f.r.Float64() < probability
+}
+
+func (f *Faker) FirstName() string {
+	return f.Pick(firstNames)
+}
+
+func (f *Faker) LastName() string {
+	return f.Pick(lastNames)
+}
+
+func (f *Faker) Name() string {
+	return f.FirstName() + " " + f.LastName()
+}
+
+func (f *Faker) City() string {
+	return f.Pick(cities)
+}
+
+// Username addan "ayse.yilmaz42" biçiminde ASCII bir kullanıcı adı üretir.
+func (f *Faker) Username(firstName, lastName string) string {
+	return turkishsearch.Fold(firstName) + "." + turkishsearch.Fold(lastName) + strconv.Itoa(f.IntBetween(1, 9999))
+}
+
+func (f *Faker) Email(username string) string {
+	return username + "@" + f.Pick(emailDomains)
+}
+
+// Time from ile to arasında rastgele bir an döner.
+func (f *Faker) Time(from, to time.Time) time.Time {
+	span := to.Sub(from)
+	if span <= 0 {
+		return from
+	}
+	return from.Add(time.Duration(f.r.Int64N(int64(span))))
+}
@@ -0,0 +1,81 @@
+package fulltext
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"html"
+	"html/template"
+	"strings"
+)
+
+// Config PostgreSQL'in Türkçe kök bulma (snowball) yapılandırmasıdır.
+const Config = "turkish"
+
+// VectorColumn modelin arama alanlarından üretilen tsvector sütunudur.
+const VectorColumn = "search_vector"
+
+// ts_headline vurguları HTML yerine özel kullanım alanındaki karakterlerle işaretlenir;
+// böylece kayıt içeriği kaçışlanmadan sayfaya basılmaz.
+const (
+	HighlightStart = "\ue000"
+	HighlightStop  = "\ue001"
+)
+
+// Field aramaya dahil edilen bir sütundur. Weight ts_rank ağırlığıdır (A en yüksek, D en düşük).
+type Field struct {
+	Column string
+	Weight string
+}
+
+// VectorExpression üretilmiş sütunun ifadesini döner:
+// setweight(to_tsvector('turkish', coalesce(name::text, ”)), 'A') || ...
+func VectorExpression(fields []Field) string {
+	parts := make([]string, 0, len(fields))
+	for _, f := range fields {
+		parts = append(parts, "setweight(to_tsvector('"+Config+"'::regconfig, coalesce("+f.Column+"::text, '')), '"+weight(f)+"')")
+	}
+	return strings.Join(parts, " || ")
+}
+
+// DocumentExpression vurgulu özet (ts_headline) için alanları tek metinde birleştirir.
+func DocumentExpression(fields []Field) string {
+	columns := make([]string, 0, len(fields))
+	for _, f := range fields {
+		columns = append(columns, f.Column+"::text")
+	}
+	return "concat_ws(' ', " + strings.Join(columns, ", ") + ")"
+}
+
+// Signature alan tanımının özetidir; migrasyon sütun yorumunda saklayarak tanım
+// değiştiğinde sütunu yeniden oluşturur.
+func Signature(fields []Field) string {
+	sum := sha256.Sum256([]byte(VectorExpression(fields)))
+	return "fts:" + hex.EncodeToString(sum[:8])
+}
+
+// QueryExpression kullanıcı girdisini web arama sözdizimiyle ("tam ifade", -hariç, or) tsquery'ye çevirir.
+func QueryExpression() string {
+	return "websearch_to_tsquery('" + Config + "'::regconfig, ?)"
+}
+
+// HeadlineOptions ts_headline seçenekleridir.
+func HeadlineOptions() string {
+	return "StartSel=" + HighlightStart + ", StopSel=" + HighlightStop + ", MaxWords=20, MinWords=5, MaxFragments=2"
+}
+
+// HighlightHTML ts_headline çıktısını kaçışlayıp işaretli bölümleri <mark> ile sarar.
+func HighlightHTML(headline string) template.HTML {
+	escaped := html.EscapeString(headline)
+	escaped = strings.ReplaceAll(escaped, HighlightStart, "<mark>")
+	escaped = strings.ReplaceAll(escaped, HighlightStop, "</mark>")
+	return template.HTML(escaped)
+}
+
+func weight(f Field) string {
+	switch w := strings.ToUpper(f.Weight); w {
+	case "A", "B", "C", "D":
+		return w
+	default:
+		return "D"
+	}
+}
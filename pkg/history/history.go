@@ -0,0 +1,453 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"zatrano/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	beforeKey   = "history:before"
+	conflictKey = "history:conflict"
+	userIDKey   = "user_id"
+)
+
+// Geçmişte zaten ayrı sütunlarda tutulan ya da her güncellemede değişen alanlar
+// fark listesine alınmaz.
+var auditColumns = map[string]bool{
+	"created_at":         true,
+	"updated_at":         true,
+	"deleted_at":         true,
+	"created_by":         true,
+	"updated_by":         true,
+	"deleted_by":         true,
+	models.VersionColumn: true,
+}
+
+var maskedValue, _ = json.Marshal(models.HistoryMaskText)
+
+// Register, BaseModel'i gömen modellerdeki değişiklikleri aynı işlem içinde
+// entity_histories tablosuna yazan callback'leri ekler.
+func Register(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := cb.Create().Before("gorm:create").Register("history:before_create", captureConflicts); err != nil {
+		return err
+	}
+	if err := cb.Create().After("gorm:create").Register("history:create", afterCreate); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("history:before_update", captureBefore); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:update").Register("history:update", afterUpdate); err != nil {
+		return err
+	}
+	if err := cb.Delete().Before("gorm:delete").Register("history:before_delete", captureBefore); err != nil {
+		return err
+	}
+	return cb.Delete().After("gorm:delete").Register("history:delete", afterDelete)
+}
+
+func tracked(db *gorm.DB) bool {
+	stmt := db.Statement
+	if db.Error != nil || stmt.Schema == nil || stmt.Schema.PrioritizedPrimaryField == nil {
+		return false
+	}
+	_, ok := reflect.New(stmt.Schema.ModelType).Interface().(models.HistoryTracked)
+	return ok
+}
+
+func afterCreate(db *gorm.DB) {
+	if !tracked(db) {
+		return
+	}
+	if _, ok := db.InstanceGet(conflictKey); ok {
+		afterUpsert(db)
+		return
+	}
+	stmt := db.Statement
+
+	var entries []models.EntityHistory
+	eachRow(stmt.ReflectValue, func(row reflect.Value) {
+		id, ok := primaryKey(stmt, row)
+		if !ok {
+			return
+		}
+		entries = append(entries, newEntry(stmt, id, models.HistoryCreate, createdChanges(stmt, row)))
+	})
+	write(db, entries)
+}
+
+// captureConflicts ON CONFLICT içeren eklemelerde (upsert) çakışabilecek mevcut
+// kayıtları okur; eklemeden sonra hangi satırların eklendiği, hangilerinin
+// güncellendiği bunlarla karşılaştırılarak bulunur.
+func captureConflicts(db *gorm.DB) {
+	if !tracked(db) {
+		return
+	}
+	stmt := db.Statement
+	onConflict, ok := stmt.Clauses["ON CONFLICT"].Expression.(clause.OnConflict)
+	if !ok {
+		return
+	}
+
+	fields := stmt.Schema.PrimaryFields
+	if len(onConflict.Columns) > 0 {
+		fields = make([]*schema.Field, 0, len(onConflict.Columns))
+		for _, column := range onConflict.Columns {
+			field := stmt.Schema.LookUpField(column.Name)
+			if field == nil {
+				return
+			}
+			fields = append(fields, field)
+		}
+	}
+
+	var keys []clause.Expression
+	eachRow(stmt.ReflectValue, func(row reflect.Value) {
+		eqs := make([]clause.Expression, 0, len(fields))
+		for _, field := range fields {
+			value, isZero := field.ValueOf(stmt.Context, row)
+			if isZero && field.PrimaryKey {
+				return
+			}
+			eqs = append(eqs, clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: value})
+		}
+		keys = append(keys, clause.And(eqs...))
+	})
+	if len(keys) == 0 {
+		return
+	}
+
+	condition := clause.Or(keys...)
+	rows, err := loadRows(db, []clause.Expression{condition}, true)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+	db.InstanceSet(conflictKey, condition)
+	db.InstanceSet(beforeKey, rows)
+}
+
+// afterUpsert çakışma koşuluna uyan kayıtları yeniden okur: önceden olmayanlar
+// eklenmiş, olup değişenler güncellenmiştir. Satır kimlikleri sürücüden bağımsız
+// olsun diye eklenen değerlerden değil veritabanından alınır.
+func afterUpsert(db *gorm.DB) {
+	if db.Error != nil || db.Statement.RowsAffected == 0 {
+		return
+	}
+	stmt := db.Statement
+	v, _ := db.InstanceGet(conflictKey)
+	condition, _ := v.(clause.Expression)
+
+	beforeByID := map[uint]reflect.Value{}
+	if before, ok := takeBefore(db); ok {
+		for i := 0; i < before.Len(); i++ {
+			if id, ok := primaryKey(stmt, before.Index(i)); ok {
+				beforeByID[id] = before.Index(i)
+			}
+		}
+	}
+
+	after, err := loadRows(db, []clause.Expression{condition}, true)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+
+	var entries []models.EntityHistory
+	seen := make(map[uint]bool, after.Len())
+	for i := 0; i < after.Len(); i++ {
+		row := after.Index(i)
+		id, ok := primaryKey(stmt, row)
+		if !ok {
+			continue
+		}
+		seen[id] = true
+		old, existed := beforeByID[id]
+		if !existed {
+			entries = append(entries, newEntry(stmt, id, models.HistoryCreate, createdChanges(stmt, row)))
+			continue
+		}
+		changes := diff(stmt.Schema, snapshot(stmt.Context, stmt.Schema, old), snapshot(stmt.Context, stmt.Schema, row))
+		if len(changes) > 0 {
+			entries = append(entries, newEntry(stmt, id, models.HistoryUpdate, changes))
+		}
+	}
+
+	// Birincil anahtarı boş olduğu için çakışma koşuluna girmeyen satırlar yalnızca eklenmiş olabilir.
+	eachRow(stmt.ReflectValue, func(row reflect.Value) {
+		id, ok := primaryKey(stmt, row)
+		if !ok || seen[id] {
+			return
+		}
+		if _, existed := beforeByID[id]; !existed {
+			entries = append(entries, newEntry(stmt, id, models.HistoryCreate, createdChanges(stmt, row)))
+		}
+	})
+	write(db, entries)
+}
+
+// captureBefore güncellenecek ya da silinecek kayıtların mevcut halini, asıl sorguyla
+// aynı koşullar ve aynı işlem içinde okur.
+func captureBefore(db *gorm.DB) {
+	if !tracked(db) {
+		return
+	}
+	stmt := db.Statement
+
+	var exprs []clause.Expression
+	if where, ok := stmt.Clauses["WHERE"].Expression.(clause.Where); ok {
+		exprs = append(exprs, where.Exprs...)
+	}
+	var ids []interface{}
+	eachRow(stmt.ReflectValue, func(row reflect.Value) {
+		if id, ok := primaryKey(stmt, row); ok {
+			ids = append(ids, id)
+		}
+	})
+	if len(ids) > 0 {
+		exprs = append(exprs, clause.IN{Column: clause.PrimaryColumn, Values: ids})
+	}
+	if len(exprs) == 0 && !stmt.AllowGlobalUpdate {
+		return
+	}
+
+	rows, err := loadRows(db, exprs, stmt.Unscoped)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+	db.InstanceSet(beforeKey, rows)
+}
+
+func afterUpdate(db *gorm.DB) {
+	before, ok := takeBefore(db)
+	if !ok || db.Statement.RowsAffected == 0 {
+		return
+	}
+	stmt := db.Statement
+
+	beforeByID := make(map[uint]reflect.Value, before.Len())
+	ids := make([]interface{}, 0, before.Len())
+	for i := 0; i < before.Len(); i++ {
+		if id, ok := primaryKey(stmt, before.Index(i)); ok {
+			beforeByID[id] = before.Index(i)
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	after, err := loadRows(db, []clause.Expression{clause.IN{Column: clause.PrimaryColumn, Values: ids}}, true)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+
+	var entries []models.EntityHistory
+	for i := 0; i < after.Len(); i++ {
+		row := after.Index(i)
+		id, _ := primaryKey(stmt, row)
+		old := beforeByID[id]
+
+		action := models.HistoryUpdate
+		wasDeleted, isDeleted := deleted(stmt, old), deleted(stmt, row)
+		switch {
+		case wasDeleted && !isDeleted:
+			action = models.HistoryRestore
+		case !wasDeleted && isDeleted:
+			action = models.HistoryDelete
+		}
+
+		// Yumuşak silme bir UPDATE olsa da Delete ile aynı kaydı bırakır: kaydın son hali.
+		changes := deletedChanges(stmt, old)
+		if action != models.HistoryDelete {
+			changes = diff(stmt.Schema, snapshot(stmt.Context, stmt.Schema, old), snapshot(stmt.Context, stmt.Schema, row))
+		}
+		if len(changes) == 0 && action == models.HistoryUpdate {
+			continue
+		}
+		entries = append(entries, newEntry(stmt, id, action, changes))
+	}
+	write(db, entries)
+}
+
+func afterDelete(db *gorm.DB) {
+	before, ok := takeBefore(db)
+	if !ok || db.Statement.RowsAffected == 0 {
+		return
+	}
+	stmt := db.Statement
+
+	var entries []models.EntityHistory
+	for i := 0; i < before.Len(); i++ {
+		row := before.Index(i)
+		id, ok := primaryKey(stmt, row)
+		if !ok {
+			continue
+		}
+		entries = append(entries, newEntry(stmt, id, models.HistoryDelete, deletedChanges(stmt, row)))
+	}
+	write(db, entries)
+}
+
+func createdChanges(stmt *gorm.Statement, row reflect.Value) models.HistoryChanges {
+	changes := models.HistoryChanges{}
+	for column, value := range snapshot(stmt.Context, stmt.Schema, row) {
+		if !isNull(value) {
+			changes[column] = models.FieldChange{After: maskIfNeeded(stmt.Schema, column, value)}
+		}
+	}
+	return changes
+}
+
+func deletedChanges(stmt *gorm.Statement, row reflect.Value) models.HistoryChanges {
+	changes := models.HistoryChanges{}
+	for column, value := range snapshot(stmt.Context, stmt.Schema, row) {
+		if !isNull(value) {
+			changes[column] = models.FieldChange{Before: maskIfNeeded(stmt.Schema, column, value)}
+		}
+	}
+	return changes
+}
+
+func takeBefore(db *gorm.DB) (reflect.Value, bool) {
+	if db.Error != nil {
+		return reflect.Value{}, false
+	}
+	v, ok := db.InstanceGet(beforeKey)
+	if !ok {
+		return reflect.Value{}, false
+	}
+	rows, ok := v.(reflect.Value)
+	return rows, ok && rows.Len() > 0
+}
+
+func loadRows(db *gorm.DB, exprs []clause.Expression, unscoped bool) (reflect.Value, error) {
+	stmt := db.Statement
+	rows := reflect.New(reflect.SliceOf(stmt.Schema.ModelType))
+
+	query := db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).
+		Model(reflect.New(stmt.Schema.ModelType).Interface())
+	if unscoped {
+		query = query.Unscoped()
+	}
+	if len(exprs) > 0 {
+		query = query.Clauses(clause.Where{Exprs: exprs})
+	}
+	if err := query.Find(rows.Interface()).Error; err != nil {
+		return reflect.Value{}, err
+	}
+	return rows.Elem(), nil
+}
+
+func write(db *gorm.DB, entries []models.EntityHistory) {
+	if len(entries) == 0 {
+		return
+	}
+	if err := db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Create(&entries).Error; err != nil {
+		db.AddError(err)
+	}
+}
+
+func newEntry(stmt *gorm.Statement, id uint, action models.HistoryAction, changes models.HistoryChanges) models.EntityHistory {
+	entry := models.EntityHistory{
+		EntityType: stmt.Schema.Table,
+		EntityID:   id,
+		Action:     action,
+		Changes:    changes,
+	}
+	if userID, ok := stmt.Context.Value(userIDKey).(uint); ok && userID != 0 {
+		entry.ActorID = &userID
+	}
+	return entry
+}
+
+func eachRow(value reflect.Value, fn func(row reflect.Value)) {
+	value = reflect.Indirect(value)
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			fn(reflect.Indirect(value.Index(i)))
+		}
+	case reflect.Struct:
+		fn(value)
+	}
+}
+
+func primaryKey(stmt *gorm.Statement, row reflect.Value) (uint, bool) {
+	value, isZero := stmt.Schema.PrioritizedPrimaryField.ValueOf(stmt.Context, reflect.Indirect(row))
+	if isZero {
+		return 0, false
+	}
+	id, ok := value.(uint)
+	return id, ok
+}
+
+func deleted(stmt *gorm.Statement, row reflect.Value) bool {
+	field := stmt.Schema.LookUpField("deleted_at")
+	if field == nil {
+		return false
+	}
+	_, isZero := field.ValueOf(stmt.Context, row)
+	return !isZero
+}
+
+func trackedField(field *schema.Field) bool {
+	return field.DBName != "" &&
+		!field.PrimaryKey &&
+		(field.Creatable || field.Updatable) &&
+		field.Tag.Get(models.HistoryTag) != models.HistoryTagSkip &&
+		!auditColumns[field.DBName]
+}
+
+func snapshot(ctx context.Context, s *schema.Schema, row reflect.Value) map[string]json.RawMessage {
+	values := make(map[string]json.RawMessage, len(s.Fields))
+	for _, field := range s.Fields {
+		if !trackedField(field) {
+			continue
+		}
+		value, _ := field.ValueOf(ctx, row)
+		b, err := json.Marshal(value)
+		if err != nil {
+			continue
+		}
+		values[field.DBName] = b
+	}
+	return values
+}
+
+func diff(s *schema.Schema, before, after map[string]json.RawMessage) models.HistoryChanges {
+	changes := models.HistoryChanges{}
+	for column, value := range after {
+		old := before[column]
+		if string(old) == string(value) {
+			continue
+		}
+		changes[column] = models.FieldChange{
+			Before: maskIfNeeded(s, column, old),
+			After:  maskIfNeeded(s, column, value),
+		}
+	}
+	return changes
+}
+
+func isNull(value json.RawMessage) bool {
+	return len(value) == 0 || string(value) == "null"
+}
+
+func maskIfNeeded(s *schema.Schema, column string, value json.RawMessage) json.RawMessage {
+	field := s.LookUpField(column)
+	if field != nil && field.Tag.Get(models.HistoryTag) == models.HistoryTagMask {
+		return maskedValue
+	}
+	return value
+}
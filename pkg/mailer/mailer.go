@@ -0,0 +1,104 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"zatrano/configs/envconfig"
+	"zatrano/configs/logconfig"
+
+	"go.uber.org/zap"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+var ErrMailerNotInitialized = errors.New("mailer başlatılmamış")
+
+var Default Mailer
+
+func InitMailer() {
+	from := envconfig.GetEnvWithDefault("MAIL_FROM", "no-reply@zatrano.local")
+	driver := strings.ToLower(envconfig.GetEnvWithDefault("MAIL_DRIVER", "log"))
+
+	switch driver {
+	case "file":
+		dir := envconfig.GetEnvWithDefault("MAIL_FILE_PATH", "./storage/mails")
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			panic("Mail klasörü oluşturulamadı: " + dir + " | Hata: " + err.Error())
+		}
+		Default = &FileMailer{From: from, Dir: dir}
+	default:
+		driver = "log"
+		Default = &LogMailer{From: from}
+	}
+
+	logconfig.SLog.Infow("Mailer başlatıldı", "driver", driver, "from", from)
+}
+
+func Send(ctx context.Context, msg Message) error {
+	if Default == nil {
+		return ErrMailerNotInitialized
+	}
+	return Default.Send(ctx, msg)
+}
+
+type LogMailer struct {
+	From string
+}
+
+func (m *LogMailer) Send(_ context.Context, msg Message) error {
+	logconfig.Log.Info("E-posta gönderildi (log sürücüsü)",
+		zap.String("from", m.From),
+		zap.String("to", msg.To),
+		zap.String("subject", msg.Subject),
+		zap.String("body", msg.Body),
+	)
+	return nil
+}
+
+type FileMailer struct {
+	From string
+	Dir  string
+}
+
+func (m *FileMailer) Send(_ context.Context, msg Message) error {
+	now := time.Now()
+	name := fmt.Sprintf("%s_%s.eml", now.Format("20060102_150405.000000"), sanitizeFileName(msg.To))
+	content := "From: " + m.From + "\r\n" +
+		"To: " + msg.To + "\r\n" +
+		"Subject: " + msg.Subject + "\r\n" +
+		"Date: " + now.Format(time.RFC1123Z) + "\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n\r\n" +
+		msg.Body + "\r\n"
+
+	path := filepath.Join(m.Dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return err
+	}
+	logconfig.Log.Info("E-posta dosyaya yazıldı", zap.String("to", msg.To), zap.String("path", path))
+	return nil
+}
+
+func sanitizeFileName(str string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, str)
+}
@@ -0,0 +1,408 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+// Target şema farkı alınacak bir modeldir. ManagedColumns ve ManagedIndexes modelde
+// alan olarak tanımlı olmayan ama başka bir migrasyonun yönettiği nesnelerdir
+// (ör. search_vector); bunların kaldırılması önerilmez.
+type Target struct {
+	Model          interface{}
+	ManagedColumns []string
+	ManagedIndexes []string
+}
+
+// Change modelle veritabanı arasındaki tek bir farktır. Destructive değişiklikler veri
+// kaybına ya da mevcut verilerle hataya yol açabilir. Down boşsa geri alma elle
+// yazılmalıdır.
+type Change struct {
+	Table       string
+	Summary     string
+	Up          []string
+	Down        []string
+	Destructive bool
+}
+
+// Diff hedef modelleri veritabanının mevcut şemasıyla karşılaştırır: eksik tablolar,
+// sütunlar, indeksler ve kısıtlar eklenir; tanımı farklı sütunlar değiştirilir; modelde
+// olmayan sütun ve indeksler kaldırılmak üzere yıkıcı değişiklik olarak döner.
+// Komutlar gorm'un migrator'ı ile üretilir, veritabanında çalıştırılmaz. Karşılaştırma
+// ancak veritabanı yalnızca migrasyonlarla kurulmuşsa migrasyonların eksiğini verir.
+// gorm'un sorgu günlüğü kapatılır; sürücülerin Debug ile açtığı okumalar da çıktıya
+// karışmaz.
+//
+// SQLite'ta sütun değişiklikleri tabloyu o anki tanımından yeniden kurar; sonraki
+// adımlar önceki adımları görmezse yeniden kurulan tablo onları geri alır. Bu yüzden
+// SQLite'ta karşılaştırma geri alınan bir işlem içinde yapılır ve yıkıcı olmayan her
+// adım bir sonrakinden önce bu işlemde uygulanır.
+func Diff(ctx context.Context, db *gorm.DB, targets ...Target) ([]Change, error) {
+	db = db.Session(&gorm.Session{Context: ctx, Logger: logger.Discard})
+	if db.Dialector.Name() != "sqlite" {
+		return diffTargets(db, false, targets)
+	}
+
+	var changes []Change
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// İşlem geri alınacağı için yeniden kurulan tablolara başvuran satırlar denetlenmez.
+		if err := tx.Exec("PRAGMA defer_foreign_keys = ON").Error; err != nil {
+			return err
+		}
+		var err error
+		if changes, err = diffTargets(tx, true, targets); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	if err != nil && !errors.Is(err, errRollback) {
+		return nil, err
+	}
+	return changes, nil
+}
+
+var errRollback = errors.New("fark işlemi geri alındı")
+
+func diffTargets(db *gorm.DB, replay bool, targets []Target) ([]Change, error) {
+	var changes []Change
+	for _, t := range targets {
+		tableChanges, err := diffTable(db, replay, t)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, tableChanges...)
+	}
+	return changes, nil
+}
+
+func diffTable(db *gorm.DB, replay bool, t Target) ([]Change, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(t.Model); err != nil {
+		return nil, fmt.Errorf("model çözümlenemedi: %w", err)
+	}
+	s := stmt.Schema
+	table := s.Table
+	m := db.Migrator()
+
+	d := differ{db: db, table: table, replay: replay}
+	if !m.HasTable(t.Model) {
+		d.add(table+" tablosu oluşturulacak", false,
+			func(tx *gorm.DB) error { return tx.Migrator().CreateTable(t.Model) },
+			func(tx *gorm.DB) error { return tx.Migrator().DropTable(t.Model) })
+		return d.changes, d.err
+	}
+
+	columnTypes, err := m.ColumnTypes(t.Model)
+	if err != nil {
+		return nil, fmt.Errorf("%s sütunları okunamadı: %w", table, err)
+	}
+	columns := make(map[string]gorm.ColumnType, len(columnTypes))
+	for _, c := range columnTypes {
+		columns[c.Name()] = c
+	}
+
+	known := make(map[string]bool)
+	for _, dbName := range s.DBNames {
+		known[dbName] = true
+		field := s.FieldsByDBName[dbName]
+		if field.IgnoreMigration {
+			continue
+		}
+		column, ok := columns[dbName]
+		if !ok {
+			summary := table + "." + dbName + " sütunu eklenecek"
+			if field.NotNull && !field.HasDefaultValue {
+				summary += " (NOT NULL ve varsayılanı yok; dolu tabloda başarısız olur)"
+			}
+			d.add(summary, false,
+				func(tx *gorm.DB) error { return tx.Migrator().AddColumn(t.Model, dbName) },
+				func(tx *gorm.DB) error { return tx.Migrator().DropColumn(t.Model, dbName) })
+			continue
+		}
+		summary := table + "." + dbName + " sütununun tanımı değişecek (şu an: " + describeColumn(column) + ")"
+		reason := narrowing(m, field, column)
+		if reason != "" {
+			summary += "; " + reason
+		}
+		d.add(summary, reason != "",
+			func(tx *gorm.DB) error { return tx.Migrator().MigrateColumn(t.Model, field, column) },
+			nil)
+	}
+
+	for _, name := range t.ManagedColumns {
+		known[name] = true
+	}
+	for _, c := range columnTypes {
+		if known[c.Name()] {
+			continue
+		}
+		name := c.Name()
+		d.add(table+"."+name+" sütunu modelde yok; kaldırılırsa verisi silinir", true,
+			func(tx *gorm.DB) error { return tx.Migrator().DropColumn(t.Model, name) },
+			nil)
+	}
+
+	if !db.DisableForeignKeyConstraintWhenMigrating && !db.IgnoreRelationshipsWhenMigrating {
+		for _, rel := range s.Relationships.Relations {
+			if rel.Field.IgnoreMigration {
+				continue
+			}
+			constraint := rel.ParseConstraint()
+			if constraint == nil || constraint.Schema != s || m.HasConstraint(t.Model, constraint.Name) {
+				continue
+			}
+			d.addConstraint(t.Model, constraint.Name)
+		}
+	}
+	for _, chk := range s.ParseCheckConstraints() {
+		if !m.HasConstraint(t.Model, chk.Name) {
+			d.addConstraint(t.Model, chk.Name)
+		}
+	}
+
+	indexes := make(map[string]bool)
+	for _, idx := range s.ParseIndexes() {
+		indexes[idx.Name] = true
+		if m.HasIndex(t.Model, idx.Name) {
+			continue
+		}
+		name := idx.Name
+		d.add(table+" tablosuna "+name+" indeksi eklenecek", false,
+			func(tx *gorm.DB) error { return tx.Migrator().CreateIndex(t.Model, name) },
+			func(tx *gorm.DB) error { return tx.Migrator().DropIndex(t.Model, name) })
+	}
+	for _, name := range t.ManagedIndexes {
+		indexes[name] = true
+	}
+	for _, field := range s.Fields {
+		if field.Unique {
+			indexes[db.NamingStrategy.UniqueName(table, field.DBName)] = true
+		}
+	}
+
+	current, err := m.GetIndexes(t.Model)
+	if err != nil {
+		return nil, fmt.Errorf("%s indeksleri okunamadı: %w", table, err)
+	}
+	for _, idx := range current {
+		if primary, _ := idx.PrimaryKey(); primary || indexes[idx.Name()] || isAutoIndex(idx.Name()) {
+			continue
+		}
+		name := idx.Name()
+		d.add(table+"."+name+" indeksi modelde yok; kaldırılacak", true,
+			func(tx *gorm.DB) error { return tx.Migrator().DropIndex(t.Model, name) },
+			nil)
+	}
+	return d.changes, d.err
+}
+
+// narrowing sütun değişikliğinin mevcut veriyi kaybettirebileceği ya da mevcut veriyle
+// başarısız olabileceği durumun açıklamasını döner; genişletme, varsayılan değer ve
+// NULL'a izin verme için boş döner. Tür karşılaştırması gorm'un MigrateColumn'uyla
+// aynıdır.
+func narrowing(m gorm.Migrator, field *schema.Field, column gorm.ColumnType) string {
+	fullDataType := strings.TrimSpace(strings.ToLower(m.FullDataTypeOf(field).SQL))
+	realDataType := strings.ToLower(column.DatabaseTypeName())
+	sameType := fullDataType == realDataType || strings.HasPrefix(fullDataType, realDataType)
+	for _, alias := range m.GetTypeAliases(realDataType) {
+		sameType = sameType || strings.HasPrefix(fullDataType, alias)
+	}
+	if !sameType {
+		return "tür değişiyor, mevcut değerler dönüştürülemeyebilir"
+	}
+	if length, ok := column.Length(); ok && field.Size > 0 && int64(field.Size) < length {
+		return fmt.Sprintf("uzunluk %d iken %d olacak, uzun değerler kesilebilir", length, field.Size)
+	}
+	if precision, _, ok := column.DecimalSize(); ok && field.Precision > 0 && int64(field.Precision) < precision {
+		return fmt.Sprintf("hassasiyet %d iken %d olacak, değerler yuvarlanabilir", precision, field.Precision)
+	}
+	if nullable, ok := column.Nullable(); ok && nullable && field.NotNull && !field.PrimaryKey {
+		return "NOT NULL oluyor, boş değerli satırlarda başarısız olur"
+	}
+	return ""
+}
+
+// differ değişiklikleri toplar; ilk hatadan sonra yeni değişiklik eklemez. replay
+// açıksa yıkıcı olmayan değişikliklerin komutları toplandıktan sonra çalıştırılır.
+type differ struct {
+	db      *gorm.DB
+	table   string
+	replay  bool
+	changes []Change
+	err     error
+}
+
+// withIndexes SQLite'ta sütun değiştirme, silme ve kısıt işlemleri tabloyu yeni adla
+// kurup eskisini siler; eski tablonun indeksleri de silindiğinden yeni tabloda hâlâ
+// bulunan sütunlardaki indeksler komutların sonuna eklenir. indexes komutlar
+// toplanmadan önceki indekslerdir.
+func (d *differ) withIndexes(statements []string, indexes []gorm.Index) ([]string, error) {
+	created := ""
+	recreated := false
+	for _, statement := range statements {
+		if strings.HasPrefix(statement, "CREATE TABLE `"+d.table+"__temp`") {
+			created = statement
+		}
+		recreated = recreated || statement == "DROP TABLE `"+d.table+"`"
+	}
+	if !recreated {
+		return statements, nil
+	}
+	for _, idx := range indexes {
+		if primary, _ := idx.PrimaryKey(); primary || isAutoIndex(idx.Name()) {
+			continue
+		}
+		kept := true
+		for _, column := range idx.Columns() {
+			kept = kept && strings.Contains(created, "`"+column+"`")
+		}
+		if !kept {
+			continue
+		}
+		var sql string
+		err := d.db.Raw("SELECT sql FROM sqlite_master WHERE type = ? AND name = ?", "index", idx.Name()).Row().Scan(&sql)
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, sql)
+	}
+	return statements, nil
+}
+
+// add up ve down'ın üreteceği komutları toplar; up bir komut üretmezse değişiklik yoktur.
+func (d *differ) add(summary string, destructive bool, up, down func(tx *gorm.DB) error) {
+	if d.err != nil {
+		return
+	}
+	upStatements, err := d.capture(up)
+	if err != nil {
+		d.err = fmt.Errorf("%s: %w", summary, err)
+		return
+	}
+	if len(upStatements) == 0 {
+		return
+	}
+	if d.replay && !destructive {
+		for _, statement := range upStatements {
+			if err := d.db.Exec(statement).Error; err != nil {
+				d.err = fmt.Errorf("%s: %w", summary, err)
+				return
+			}
+		}
+	}
+	var downStatements []string
+	if down != nil {
+		if downStatements, err = d.capture(down); err != nil {
+			d.err = fmt.Errorf("%s: %w", summary, err)
+			return
+		}
+	}
+	d.changes = append(d.changes, Change{
+		Table:       d.table,
+		Summary:     summary,
+		Up:          upStatements,
+		Down:        downStatements,
+		Destructive: destructive,
+	})
+}
+
+// capture fn'in komutlarını toplar; SQLite'ta tabloyu yeniden kuran komutlara
+// kaybolan indeksleri ekler.
+func (d *differ) capture(fn func(tx *gorm.DB) error) ([]string, error) {
+	if d.db.Dialector.Name() != "sqlite" {
+		return Capture(d.db, fn)
+	}
+	indexes, err := d.db.Migrator().GetIndexes(d.table)
+	if err != nil {
+		return nil, err
+	}
+	statements, err := Capture(d.db, fn)
+	if err != nil {
+		return nil, err
+	}
+	return d.withIndexes(statements, indexes)
+}
+
+func (d *differ) addConstraint(model interface{}, name string) {
+	d.add(d.table+" tablosuna "+name+" kısıtı eklenecek", false,
+		func(tx *gorm.DB) error { return tx.Migrator().CreateConstraint(model, name) },
+		func(tx *gorm.DB) error { return tx.Migrator().DropConstraint(model, name) })
+}
+
+func describeColumn(c gorm.ColumnType) string {
+	description := strings.ToLower(c.DatabaseTypeName())
+	if length, ok := c.Length(); ok && length > 0 {
+		description += fmt.Sprintf("(%d)", length)
+	}
+	if nullable, ok := c.Nullable(); ok && !nullable {
+		description += " not null"
+	}
+	if value, ok := c.DefaultValue(); ok {
+		description += " default " + value
+	}
+	return description
+}
+
+// isAutoIndex SQLite'ın benzersiz kısıtlar için kendi oluşturduğu indeksleri ayırt eder.
+func isAutoIndex(name string) bool {
+	return strings.HasPrefix(name, "sqlite_autoindex_")
+}
+
+// WriteDiff değişiklikleri gözden geçirilmek üzere up ve down SQL betiklerine yazar.
+// Yıkıcı değişikliklerin komutları yorum satırı olarak yazılır; gerekiyorsa elle
+// açılır. Down betiği değişiklikleri ters sırayla geri alır.
+func WriteDiff(up, down io.Writer, changes []Change) error {
+	header := "-- Modellerle veritabanı arasındaki farktan üretildi; uygulamadan önce gözden geçirin.\n" +
+		"-- YIKICI ile işaretlenen adımlar yorum satırı olarak bırakıldı.\n"
+	if _, err := io.WriteString(up, header); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(down, header); err != nil {
+		return err
+	}
+
+	for _, c := range changes {
+		if err := writeStep(up, c, c.Up); err != nil {
+			return err
+		}
+	}
+	for i := len(changes) - 1; i >= 0; i-- {
+		c := changes[i]
+		if len(c.Down) == 0 {
+			if _, err := fmt.Fprintf(down, "\n-- %s\n-- Geri alma elle yazılmalı.\n", c.Summary); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := writeStep(down, Change{Summary: "Geri alınan: " + c.Summary}, c.Down); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeStep(w io.Writer, c Change, statements []string) error {
+	prefix := ""
+	summary := c.Summary
+	if c.Destructive {
+		prefix = "-- "
+		summary = "YIKICI " + summary
+	}
+	if _, err := fmt.Fprintf(w, "\n-- %s\n", summary); err != nil {
+		return err
+	}
+	for _, statement := range statements {
+		statement = prefix + strings.ReplaceAll(statement, "\n", "\n"+prefix)
+		if _, err := fmt.Fprintf(w, "%s;\n", statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// dryRunPool okuma sorgularını gerçek bağlantıya iletir, Exec ile gelen değişiklikleri
+// ise çalıştırmadan emit'e verir.
+type dryRunPool struct {
+	gorm.ConnPool
+	dialector gorm.Dialector
+	emit      func(statement string)
+}
+
+func (p *dryRunPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	p.emit(strings.TrimRight(strings.TrimSpace(p.dialector.Explain(query, args...)), ";"))
+	return driver.RowsAffected(0), nil
+}
+
+// dryRunSession db'nin yazma komutlarını emit'e yönlendiren bir kopyasını döner. Komutlar
+// yalnızca emit'e gider; gorm'un sorgu günlüğü kapatılır, sürücülerin Debug ile açtığı
+// okumalar da yazdırılan SQL'e karışmaz.
+func dryRunSession(db *gorm.DB, emit func(statement string)) *gorm.DB {
+	// Context verilen Session Statement'ı kopyalar; aksi halde ConnPool değişikliği
+	// db'nin ve onu paylaşan oturumların Statement'ına da yansır.
+	session := db.Session(&gorm.Session{NewDB: true, Context: db.Statement.Context, Logger: logger.Discard})
+	session.Statement.ConnPool = &dryRunPool{ConnPool: session.Statement.ConnPool, dialector: session.Dialector, emit: emit}
+	return session
+}
+
+// Capture fn'in çalıştırdığı yazma komutlarını çalıştırmadan toplar. Okuma sorguları
+// veritabanında gerçekten çalışır; böylece gorm'un şema karşılaştırmaları mevcut
+// duruma göre yapılır.
+func Capture(db *gorm.DB, fn func(tx *gorm.DB) error) ([]string, error) {
+	var statements []string
+	err := fn(dryRunSession(db, func(statement string) {
+		statements = append(statements, statement)
+	}))
+	return statements, err
+}
+
+func (p *dryRunPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	return &dryRunTx{dryRunPool: p}, nil
+}
+
+// dryRunTx gorm'un işlem akışını sürdürür; gerçek bir işlem açılmaz.
+type dryRunTx struct {
+	*dryRunPool
+}
+
+func (t *dryRunTx) Commit() error {
+	return nil
+}
+
+func (t *dryRunTx) Rollback() error {
+	return nil
+}
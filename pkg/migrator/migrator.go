@@ -0,0 +1,426 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"time"
+
+	"zatrano/configs/logconfig"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// lockKey PostgreSQL advisory lock anahtarı, lockName MySQL GET_LOCK adıdır. Aynı
+// veritabanını kullanan tüm uygulama örnekleri aynı kilidi bekler.
+const (
+	lockKey          int64 = 7_391_204_551
+	lockName               = "schema_migrations"
+	mysqlLockTimeout       = 600
+)
+
+var versionPattern = regexp.MustCompile(`^\d{14}$`)
+
+// ErrIrreversible Down adımı tanımlanmamış bir migrasyon geri alınmak istendiğinde döner.
+var ErrIrreversible = errors.New("migrasyon geri alınamaz")
+
+// ErrMaybePartial MySQL'de yarıda kalan bir migrasyonun hatasına eklenir: DDL komutları
+// işlemi kendiliğinden commit ettiği için değişikliklerin bir kısmı schema_migrations
+// kaydı olmadan kalıcı olmuş olabilir. Şema elle kontrol edilip düzeltilmelidir.
+var ErrMaybePartial = errors.New("MySQL'de şema değişikliklerinin bir kısmı kayıt defterine yazılmadan uygulanmış olabilir")
+
+// Migration sürümüyle (YYYYMMDDHHMMSS) sıralanan tek bir şema değişikliğidir. Up ve
+// Down kendi işlemleri içinde çalışır; Down tanımlanmamışsa migrasyon geri alınamaz.
+// MySQL DDL komutlarını işlem içinde geri alamaz; orada birden çok DDL içeren bir
+// migrasyon yarıda kalırsa önceki komutlar kalıcı olur (bkz. ErrMaybePartial).
+type Migration struct {
+	Version string
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+func (m Migration) String() string {
+	return m.Version + "_" + m.Name
+}
+
+// Record uygulanmış migrasyonların tutulduğu schema_migrations tablosunun satırıdır.
+type Record struct {
+	Version   string    `gorm:"primaryKey;size:14"`
+	Name      string    `gorm:"size:255;not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (Record) TableName() string {
+	return "schema_migrations"
+}
+
+// Status bir migrasyonun uygulanma durumudur. Missing, kayıt defterinde olup kodda
+// karşılığı bulunmayan migrasyonları belirtir.
+type Status struct {
+	Version   string
+	Name      string
+	AppliedAt *time.Time
+	Missing   bool
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+	dryRun     io.Writer
+	// dryRunLedger deneme çalıştırmasında kayıt defteri tablosunun SQL'inin bir kez yazılması içindir.
+	dryRunLedger bool
+}
+
+// New migrasyonları sürüm sırasına dizer; geçersiz ya da tekrarlanan sürümlerde hata döner.
+func New(db *gorm.DB, migrations []Migration) (*Migrator, error) {
+	sorted := append([]Migration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	for i, m := range sorted {
+		if !versionPattern.MatchString(m.Version) {
+			return nil, fmt.Errorf("migrasyon sürümü geçersiz (YYYYMMDDHHMMSS bekleniyor): %s", m)
+		}
+		if m.Up == nil {
+			return nil, fmt.Errorf("migrasyonun Up adımı yok: %s", m)
+		}
+		if i > 0 && sorted[i-1].Version == m.Version {
+			return nil, fmt.Errorf("aynı sürüme sahip iki migrasyon var: %s, %s", sorted[i-1], m)
+		}
+	}
+	return &Migrator{db: db, migrations: sorted}, nil
+}
+
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// DryRun değişiklik yapan sorguları çalıştırmak yerine w'ye yazan bir kopya döner.
+// Şemayı okuyan sorgular gerçekten çalışır; böylece çıktı veritabanının mevcut
+// durumuna göre üretilir. Bekleyen migrasyonlar birbirinin değişikliklerine
+// dayanıyorsa sonraki migrasyonların çıktısı bu değişiklikleri göremez.
+func (m *Migrator) DryRun(w io.Writer) *Migrator {
+	db := dryRunSession(m.db, func(statement string) {
+		fmt.Fprintf(w, "%s;\n", statement)
+	})
+	return &Migrator{db: db, migrations: m.migrations, dryRun: w}
+}
+
+// Status bilinen tüm migrasyonları ve kayıt defterinde olup kodda bulunmayanları
+// sürüm sırasıyla döner.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	records, err := m.records(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		s := Status{Version: mig.Version, Name: mig.Name}
+		if r, ok := records[mig.Version]; ok {
+			appliedAt := r.AppliedAt
+			s.AppliedAt = &appliedAt
+			delete(records, mig.Version)
+		}
+		statuses = append(statuses, s)
+	}
+	for _, r := range records {
+		appliedAt := r.AppliedAt
+		statuses = append(statuses, Status{Version: r.Version, Name: r.Name, AppliedAt: &appliedAt, Missing: true})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// Pending henüz uygulanmamış migrasyonları sürüm sırasıyla döner.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	records, err := m.records(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, mig := range m.migrations {
+		if _, ok := records[mig.Version]; !ok {
+			pending = append(pending, mig)
+		}
+	}
+	return pending, nil
+}
+
+// Applied uygulanmış migrasyonları en yeniden eskiye döner. Kayıt defterinde olup
+// kodda bulunmayan bir migrasyon varsa hata döner; geri alınacak adım bilinmez.
+func (m *Migrator) Applied(ctx context.Context) ([]Migration, error) {
+	records, err := m.records(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[string]Migration, len(m.migrations))
+	for _, mig := range m.migrations {
+		byVersion[mig.Version] = mig
+	}
+
+	applied := make([]Migration, 0, len(records))
+	for version, r := range records {
+		mig, ok := byVersion[version]
+		if !ok {
+			return nil, fmt.Errorf("uygulanmış migrasyonun kodu bulunamadı: %s_%s", r.Version, r.Name)
+		}
+		applied = append(applied, mig)
+	}
+	sort.Slice(applied, func(i, j int) bool { return applied[i].Version > applied[j].Version })
+	return applied, nil
+}
+
+// Up bekleyen migrasyonlardan en fazla limit tanesini (0 ise tümünü) sırayla uygular
+// ve uygulananların sayısını döner. Her migrasyon kendi işleminde ve migrasyon kilidi
+// altında çalışır; kilidi bekleyen diğer örnek aynı migrasyonu tekrar uygulamaz.
+// PostgreSQL ve SQLite'ta migrasyon ve kaydı birlikte commit edilir ya da geri alınır;
+// MySQL'de bu garanti yoktur ve yarıda kalan migrasyon ErrMaybePartial ile döner.
+func (m *Migrator) Up(ctx context.Context, limit int) (int, error) {
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if limit > 0 && len(pending) > limit {
+		pending = pending[:limit]
+	}
+
+	count := 0
+	for _, mig := range pending {
+		applied, err := m.run(ctx, mig, true)
+		if err != nil {
+			return count, err
+		}
+		if applied {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Down son uygulanan migrasyonlardan en fazla limit tanesini (0 ise tümünü) en yeniden
+// başlayarak geri alır ve geri alınanların sayısını döner. Up'taki gibi MySQL'de yarıda
+// kalan geri alma ErrMaybePartial ile döner.
+func (m *Migrator) Down(ctx context.Context, limit int) (int, error) {
+	applied, err := m.Applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if limit > 0 && len(applied) > limit {
+		applied = applied[:limit]
+	}
+
+	count := 0
+	for _, mig := range applied {
+		if mig.Down == nil {
+			return count, fmt.Errorf("%w: %s", ErrIrreversible, mig)
+		}
+		reverted, err := m.run(ctx, mig, false)
+		if err != nil {
+			return count, err
+		}
+		if reverted {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Redo son uygulanan migrasyonu geri alıp yeniden uygular.
+func (m *Migrator) Redo(ctx context.Context) (Migration, error) {
+	applied, err := m.Applied(ctx)
+	if err != nil {
+		return Migration{}, err
+	}
+	if len(applied) == 0 {
+		return Migration{}, errors.New("geri alınacak uygulanmış migrasyon yok")
+	}
+	last := applied[0]
+	if last.Down == nil {
+		return last, fmt.Errorf("%w: %s", ErrIrreversible, last)
+	}
+	if _, err := m.run(ctx, last, false); err != nil {
+		return last, err
+	}
+	_, err = m.run(ctx, last, true)
+	return last, err
+}
+
+// run migrasyonu tek bir işlem içinde uygular ya da geri alır. Kilit alındıktan sonra
+// kayıt defteri yeniden okunur; iş başka bir örnek tarafından yapılmışsa atlanır.
+func (m *Migrator) run(ctx context.Context, mig Migration, up bool) (bool, error) {
+	done := false
+	started := false
+	start := time.Now()
+	err := m.locked(ctx, func(tx *gorm.DB) error {
+		hasLedger := tx.Migrator().HasTable(&Record{})
+		if !hasLedger && !m.dryRunLedger {
+			if err := tx.AutoMigrate(&Record{}); err != nil {
+				return fmt.Errorf("schema_migrations tablosu oluşturulamadı: %w", err)
+			}
+			m.dryRunLedger = m.dryRun != nil
+		}
+
+		if m.dryRun != nil {
+			direction := "up"
+			if !up {
+				direction = "down"
+			}
+			fmt.Fprintf(m.dryRun, "-- %s: %s\n", direction, mig)
+		} else if hasLedger {
+			var count int64
+			if err := tx.Model(&Record{}).Where("version = ?", mig.Version).Count(&count).Error; err != nil {
+				return err
+			}
+			if up == (count > 0) {
+				return nil
+			}
+		}
+
+		started = m.dryRun == nil
+		if up {
+			if err := mig.Up(tx); err != nil {
+				return err
+			}
+			done = true
+			return tx.Create(&Record{Version: mig.Version, Name: mig.Name, AppliedAt: time.Now().UTC()}).Error
+		}
+		if err := mig.Down(tx); err != nil {
+			return err
+		}
+		done = true
+		return tx.Where("version = ?", mig.Version).Delete(&Record{}).Error
+	})
+	if err != nil {
+		direction := "uygulanamadı"
+		if !up {
+			direction = "geri alınamadı"
+		}
+		if started && m.db.Dialector.Name() == "mysql" {
+			logconfig.Log.Error("MySQL migrasyonu yarıda kaldı; şemayı elle kontrol edin",
+				zap.String("version", mig.Version),
+				zap.String("name", mig.Name),
+				zap.Error(err),
+			)
+			return false, fmt.Errorf("migrasyon %s: %s: %w: %w", direction, mig, ErrMaybePartial, err)
+		}
+		return false, fmt.Errorf("migrasyon %s: %s: %w", direction, mig, err)
+	}
+
+	if done && m.dryRun == nil {
+		message := "Migrasyon uygulandı"
+		if !up {
+			message = "Migrasyon geri alındı"
+		}
+		logconfig.Log.Info(message,
+			zap.String("version", mig.Version),
+			zap.String("name", mig.Name),
+			zap.Duration("duration", time.Since(start)),
+		)
+	}
+	return done, nil
+}
+
+func (m *Migrator) records(ctx context.Context) (map[string]Record, error) {
+	var rows []Record
+	err := m.locked(ctx, func(tx *gorm.DB) error {
+		if !tx.Migrator().HasTable(&Record{}) {
+			return nil
+		}
+		return tx.Order("version").Find(&rows).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("migrasyon kayıtları okunamadı: %w", err)
+	}
+	records := make(map[string]Record, len(rows))
+	for _, r := range rows {
+		records[r.Version] = r
+	}
+	return records, nil
+}
+
+// WithinLock fn'i migrasyonlarla aynı kilit ve işlem altında çalıştırır; migrasyon
+// kayıt defterine girmeyen şema eşitlemeleri (ör. enum tipleri) için kullanılır.
+// Deneme çalıştırmasında fn'in yazma komutları çalıştırılmadan yazdırılır.
+func (m *Migrator) WithinLock(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return m.locked(ctx, fn)
+}
+
+// locked fn'i migrasyon kilidi alınmış bir işlem içinde çalıştırır. PostgreSQL'de kilit işlemle birlikte bırakılır;
+// MySQL'de oturum kilidi işlem bitmeden açıkça bırakılır. SQLite'ta işlemler yazma
+// kilidini baştan aldığı için ayrı bir kilit gerekmez. Deneme çalıştırmasında kilit alınmaz.
+func (m *Migrator) locked(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	if m.dryRun == nil && m.db.Dialector.Name() == "sqlite" {
+		return m.withoutForeignKeys(ctx, fn)
+	}
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if m.dryRun != nil {
+			return fn(tx)
+		}
+		switch tx.Dialector.Name() {
+		case "postgres":
+			// Uzun süren migrasyonlar ve kilit beklemesi bağlantının varsayılan süre sınırına takılmasın.
+			if err := tx.Exec("SET LOCAL statement_timeout = 0").Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", lockKey).Error; err != nil {
+				return fmt.Errorf("migrasyon kilidi alınamadı: %w", err)
+			}
+		case "mysql":
+			var acquired *int
+			if err := tx.Raw("SELECT GET_LOCK(?, ?)", lockName, mysqlLockTimeout).Scan(&acquired).Error; err != nil {
+				return fmt.Errorf("migrasyon kilidi alınamadı: %w", err)
+			}
+			if acquired == nil || *acquired != 1 {
+				return errors.New("migrasyon kilidi alınamadı: zaman aşımı")
+			}
+			defer tx.Exec("SELECT RELEASE_LOCK(?)", lockName)
+		}
+		return fn(tx)
+	})
+}
+
+// withoutForeignKeys SQLite'ta fn'i yabancı anahtar denetimi kapalı bir bağlantıda
+// çalıştırır. Sütun silme ya da kısıt ekleme tabloyu yeniden kurar; tabloya başvuran
+// satırlar varken eski tablonun silinmesi denetime takılır. Denetim işlem içinde
+// kapatılamadığından işlemden önce kapatılır ve işlem bitmeden foreign_key_check ile
+// bütünlük doğrulanır.
+func (m *Migrator) withoutForeignKeys(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		conn = conn.Session(&gorm.Session{NewDB: true, Context: ctx})
+		var enabled int
+		if err := conn.Raw("PRAGMA foreign_keys").Scan(&enabled).Error; err != nil {
+			return err
+		}
+		if enabled == 1 {
+			if err := conn.Exec("PRAGMA foreign_keys = OFF").Error; err != nil {
+				return err
+			}
+			defer conn.Exec("PRAGMA foreign_keys = ON")
+		}
+
+		return conn.Transaction(func(tx *gorm.DB) error {
+			if err := fn(tx); err != nil {
+				return err
+			}
+			if enabled != 1 {
+				return nil
+			}
+			var violations []struct {
+				Table  string
+				Parent string
+			}
+			if err := tx.Raw("PRAGMA foreign_key_check").Scan(&violations).Error; err != nil {
+				return err
+			}
+			if len(violations) > 0 {
+				return fmt.Errorf("yabancı anahtar ihlali: %s tablosunda %s tablosuna başvuran %d satır", violations[0].Table, violations[0].Parent, len(violations))
+			}
+			return nil
+		})
+	})
+}
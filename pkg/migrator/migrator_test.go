@@ -0,0 +1,245 @@
+package migrator
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"zatrano/configs/logconfig"
+
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testMigration Up ve Down adımlarının kaç kez çalıştığını sayar.
+type testMigration struct {
+	Migration
+	ups, downs int
+}
+
+func newTestMigration(version, name, up, down string) *testMigration {
+	tm := &testMigration{}
+	tm.Migration = Migration{
+		Version: version,
+		Name:    name,
+		Up: func(tx *gorm.DB) error {
+			tm.ups++
+			return tx.Exec(up).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			tm.downs++
+			return tx.Exec(down).Error
+		},
+	}
+	return tm
+}
+
+func testMigrations() (*testMigration, *testMigration) {
+	create := newTestMigration("20250101120000", "create_widgets",
+		"CREATE TABLE widgets (id integer PRIMARY KEY, name text NOT NULL)",
+		"DROP TABLE widgets")
+	addColor := newTestMigration("20250102120000", "add_widgets_color",
+		"ALTER TABLE widgets ADD COLUMN color text",
+		"ALTER TABLE widgets DROP COLUMN color")
+	return create, addColor
+}
+
+// openTestDB yabancı anahtar denetimi açık, dosyaya yazan bir SQLite veritabanı açar.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	if logconfig.Log == nil {
+		logconfig.Log = zap.NewNop()
+	}
+	dsn := filepath.Join(t.TempDir(), "test.db") + "?_pragma=foreign_keys(1)&_txlock=immediate"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func newTestMigrator(t *testing.T, db *gorm.DB, migrations ...Migration) *Migrator {
+	t.Helper()
+	m, err := New(db, migrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func ledgerVersions(t *testing.T, db *gorm.DB) []string {
+	t.Helper()
+	var versions []string
+	if err := db.Model(&Record{}).Order("version").Pluck("version", &versions).Error; err != nil {
+		t.Fatal(err)
+	}
+	return versions
+}
+
+func TestUpDownRedo(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	create, addColor := testMigrations()
+	m := newTestMigrator(t, db, addColor.Migration, create.Migration)
+
+	count, err := m.Up(ctx, 0)
+	if err != nil {
+		t.Fatalf("Up() hata = %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Up() = %d, want 2", count)
+	}
+	if got, want := ledgerVersions(t, db), []string{create.Version, addColor.Version}; !reflect.DeepEqual(got, want) {
+		t.Errorf("kayıt defteri = %v, want %v", got, want)
+	}
+	if !db.Migrator().HasColumn("widgets", "color") {
+		t.Error("Up() sonrası color sütunu yok")
+	}
+
+	count, err = m.Down(ctx, 1)
+	if err != nil {
+		t.Fatalf("Down() hata = %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Down() = %d, want 1", count)
+	}
+	if got, want := ledgerVersions(t, db), []string{create.Version}; !reflect.DeepEqual(got, want) {
+		t.Errorf("kayıt defteri = %v, want %v", got, want)
+	}
+	if db.Migrator().HasColumn("widgets", "color") {
+		t.Error("Down() sonrası color sütunu hâlâ var")
+	}
+	if create.downs != 0 {
+		t.Errorf("Down(1) ilk migrasyonu da geri aldı")
+	}
+
+	if _, err := m.Up(ctx, 0); err != nil {
+		t.Fatalf("Up() hata = %v", err)
+	}
+	last, err := m.Redo(ctx)
+	if err != nil {
+		t.Fatalf("Redo() hata = %v", err)
+	}
+	if last.Version != addColor.Version {
+		t.Errorf("Redo() = %s, want %s", last, addColor.Migration)
+	}
+	if addColor.ups != 3 || addColor.downs != 2 {
+		t.Errorf("add_widgets_color up/down = %d/%d, want 3/2", addColor.ups, addColor.downs)
+	}
+	if got, want := ledgerVersions(t, db), []string{create.Version, addColor.Version}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Redo() sonrası kayıt defteri = %v, want %v", got, want)
+	}
+
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("Pending() = %v, want boş", pending)
+	}
+}
+
+func TestUpSkipsRecordedMigrations(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	create, addColor := testMigrations()
+
+	// Tablo kayıt defterine yazılmış ama migrasyon bu örnekte hiç çalışmamış gibi kurulur.
+	if err := db.Exec("CREATE TABLE widgets (id integer PRIMARY KEY, name text NOT NULL)").Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&Record{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&Record{Version: create.Version, Name: create.Name}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	m := newTestMigrator(t, db, create.Migration, addColor.Migration)
+	count, err := m.Up(ctx, 0)
+	if err != nil {
+		t.Fatalf("Up() hata = %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Up() = %d, want 1", count)
+	}
+	if create.ups != 0 {
+		t.Errorf("kayıt defterindeki migrasyon yeniden çalıştı")
+	}
+	if addColor.ups != 1 {
+		t.Errorf("bekleyen migrasyon %d kez çalıştı, want 1", addColor.ups)
+	}
+
+	// Kilit alındıktan sonra kayıt defteri yeniden okunur; başka bir örneğin
+	// uyguladığı migrasyon atlanır.
+	applied, err := m.run(ctx, addColor.Migration, true)
+	if err != nil {
+		t.Fatalf("run() hata = %v", err)
+	}
+	if applied || addColor.ups != 1 {
+		t.Errorf("uygulanmış migrasyon yeniden çalıştı")
+	}
+}
+
+func TestMissingMigrationCode(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	create, addColor := testMigrations()
+	if _, err := newTestMigrator(t, db, create.Migration, addColor.Migration).Up(ctx, 0); err != nil {
+		t.Fatalf("Up() hata = %v", err)
+	}
+
+	m := newTestMigrator(t, db, create.Migration)
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status() hata = %v", err)
+	}
+	if len(statuses) != 2 || !statuses[1].Missing || statuses[1].Version != addColor.Version {
+		t.Errorf("Status() = %+v, kodu olmayan migrasyon bekleniyordu", statuses)
+	}
+
+	if _, err := m.Applied(ctx); err == nil {
+		t.Error("Applied() hata bekleniyordu")
+	}
+	if _, err := m.Down(ctx, 1); err == nil {
+		t.Error("Down() hata bekleniyordu")
+	}
+	if _, err := m.Redo(ctx); err == nil {
+		t.Error("Redo() hata bekleniyordu")
+	}
+	if create.downs != 0 || addColor.downs != 0 {
+		t.Error("kodu olmayan migrasyon varken geri alma yapıldı")
+	}
+	if got, want := ledgerVersions(t, db), []string{create.Version, addColor.Version}; !reflect.DeepEqual(got, want) {
+		t.Errorf("kayıt defteri = %v, want %v", got, want)
+	}
+}
+
+func TestSQLMigrationWithoutDriverFile(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	migrations, err := LoadSQL(fstest.MapFS{
+		"20250101120000_create_widgets.postgres.up.sql":   {Data: []byte("CREATE TABLE widgets (id serial PRIMARY KEY)")},
+		"20250101120000_create_widgets.postgres.down.sql": {Data: []byte("DROP TABLE widgets")},
+	})
+	if err != nil {
+		t.Fatalf("LoadSQL() hata = %v", err)
+	}
+
+	_, err = newTestMigrator(t, db, migrations...).Up(ctx, 0)
+	if err == nil || !strings.Contains(err.Error(), "sqlite sürücüsü için SQL dosyası yok") {
+		t.Fatalf("Up() hata = %v, eksik sürücü dosyası hatası bekleniyordu", err)
+	}
+	// Kayıt defteri tablosu da başarısız migrasyonun işlemiyle birlikte geri alınır.
+	if db.Migrator().HasTable(&Record{}) {
+		if versions := ledgerVersions(t, db); len(versions) != 0 {
+			t.Errorf("kayıt defteri = %v, want boş", versions)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package migrator
+
+import (
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// sqlFilePattern SQL migrasyon dosya adlarıdır:
+//
+//	20250101120000_add_users_phone.up.sql
+//	20250101120000_add_users_phone.down.sql
+//	20250101120000_add_users_phone.postgres.up.sql
+//
+// Sürücü adı verilen dosya yalnızca o sürücüde, sürücüsüz dosya diğerlerinde çalışır.
+// Çalışan sürücü için ne kendi dosyası ne de sürücüsüz dosya varsa migrasyon hata
+// verir; bir sürücüde bir şey yapılmayacaksa bunu yalnızca yorum içeren bir dosya
+// bildirir.
+var sqlFilePattern = regexp.MustCompile(`^(\d{14})_(\w+?)(?:\.(postgres|mysql|sqlite))?\.(up|down)\.sql$`)
+
+type sqlScripts struct {
+	name string
+	up   map[string]string
+	down map[string]string
+}
+
+// LoadSQL fsys kökündeki .sql dosyalarını migrasyonlara çevirir. Aynı sürümün
+// dosyaları tek migrasyonda birleşir; down dosyası olmayan migrasyon geri alınamaz.
+func LoadSQL(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	scripts := make(map[string]*sqlScripts)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+		m := sqlFilePattern.FindStringSubmatch(entry.Name())
+		if m == nil {
+			return nil, fmt.Errorf("SQL migrasyon dosya adı geçersiz: %s", entry.Name())
+		}
+		version, name, driver, direction := m[1], m[2], m[3], m[4]
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		s, ok := scripts[version]
+		if !ok {
+			s = &sqlScripts{name: name, up: map[string]string{}, down: map[string]string{}}
+			scripts[version] = s
+		} else if s.name != name {
+			return nil, fmt.Errorf("aynı sürüme sahip farklı SQL migrasyonları var: %s_%s, %s_%s", version, s.name, version, name)
+		}
+		if direction == "up" {
+			s.up[driver] = string(content)
+		} else {
+			s.down[driver] = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(scripts))
+	for version, s := range scripts {
+		mig := Migration{Version: version, Name: s.name, Up: execScript(s.up)}
+		if len(s.down) > 0 {
+			mig.Down = execScript(s.down)
+		}
+		migrations = append(migrations, mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// execScript çalışan sürücüye ait betiği, yoksa sürücüsüz betiği tek seferde çalıştırır.
+func execScript(scripts map[string]string) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		script, ok := scripts[tx.Dialector.Name()]
+		if !ok {
+			if script, ok = scripts[""]; !ok {
+				return fmt.Errorf("%s sürücüsü için SQL dosyası yok", tx.Dialector.Name())
+			}
+		}
+		if isEmptyScript(script) {
+			return nil
+		}
+		return tx.Exec(script).Error
+	}
+}
+
+// isEmptyScript betikte yorum satırları dışında bir şey olmadığını bildirir; MySQL
+// yalnızca yorumdan oluşan sorguları hata olarak döner.
+func isEmptyScript(script string) bool {
+	for _, line := range strings.Split(script, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "--") {
+			return false
+		}
+	}
+	return true
+}
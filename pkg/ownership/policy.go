@@ -0,0 +1,41 @@
+package ownership
+
+import "context"
+
+const contextPolicyKey = "ownership_policy"
+
+type Policy struct {
+	ActorID        uint
+	SubordinateIDs []uint
+	Bypass         bool
+}
+
+func WithPolicy(ctx context.Context, policy Policy) context.Context {
+	return context.WithValue(ctx, contextPolicyKey, policy)
+}
+
+// WithBypass sahiplik filtresini kaldırır. Oturumdaki kullanıcının kendi kaydı ya da
+// imzalı bağlantıyla yetkisi kanıtlanmış işlemler gibi politikanın kurulmadığı
+// yollarda kullanılır; politikasız sorgular reddedilir.
+func WithBypass(ctx context.Context) context.Context {
+	return WithPolicy(ctx, Policy{Bypass: true})
+}
+
+func PolicyFromContext(ctx context.Context) (Policy, bool) {
+	if ctx == nil {
+		return Policy{}, false
+	}
+	policy, ok := ctx.Value(contextPolicyKey).(Policy)
+	return policy, ok
+}
+
+func (p Policy) OwnerIDs() []uint {
+	ids := make([]uint, 0, len(p.SubordinateIDs)+1)
+	ids = append(ids, p.ActorID)
+	for _, id := range p.SubordinateIDs {
+		if id != p.ActorID {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,56 @@
+package passwordpolicy
+
+import (
+	"errors"
+	"fmt"
+	"unicode"
+
+	"zatrano/configs/envconfig"
+	"zatrano/pkg/apperrors"
+)
+
+var ErrPasswordPolicy = errors.New("şifre kurallara uymuyor")
+
+func MinLength() int {
+	return envconfig.GetEnvAsInt("PASSWORD_MIN_LENGTH", 8)
+}
+
+func Validate(password string) error {
+	if len([]rune(password)) < MinLength() {
+		return violation(fmt.Sprintf("şifre en az %d karakter olmalıdır", MinLength()))
+	}
+
+	var hasLetter, hasDigit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+	if !hasLetter {
+		return violation("şifre en az bir harf içermelidir")
+	}
+	if !hasDigit {
+		return violation("şifre en az bir rakam içermelidir")
+	}
+	return nil
+}
+
+func ValidateWithConfirmation(password, confirmation string) error {
+	if err := Validate(password); err != nil {
+		return err
+	}
+	if password != confirmation {
+		return violation("şifreler uyuşmuyor")
+	}
+	return nil
+}
+
+// violation kuralı açıklayan doğrulama hatasını döner; errors.Is(err, ErrPasswordPolicy) doğru kalır.
+func violation(message string) error {
+	err := apperrors.Validation(message, "password")
+	err.Err = ErrPasswordPolicy
+	return err
+}
@@ -0,0 +1,197 @@
+package pgenum
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"zatrano/configs/logconfig"
+	"zatrano/models"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Sync enum tipini koddaki tanıma getirir: tip yoksa oluşturur, EnumRenamer ile
+// bildirilen değerleri yeniden adlandırır ve eksik değerleri koddaki sıralarını
+// koruyarak ekler. Değer silinmez; kodda olmayan değerler için uyarı yazılır.
+// PostgreSQL dışındaki sürücülerde bir şey yapmaz.
+func Sync(tx *gorm.DB, e models.Enum) error {
+	if tx.Dialector.Name() != "postgres" {
+		return nil
+	}
+
+	name := e.EnumName()
+	desired := e.EnumValues()
+	exists, err := typeExists(tx, name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		literals := make([]string, len(desired))
+		for i, v := range desired {
+			literals[i] = quoteLiteral(v)
+		}
+		logconfig.Log.Info("Enum tipi oluşturuluyor", zap.String("enum", name), zap.Strings("values", desired))
+		return tx.Exec("CREATE TYPE " + quoteIdent(name) + " AS ENUM (" + strings.Join(literals, ", ") + ")").Error
+	}
+
+	current, err := labels(tx, name)
+	if err != nil {
+		return err
+	}
+	have := make(map[string]bool, len(current))
+	for _, v := range current {
+		have[v] = true
+	}
+
+	if r, ok := e.(models.EnumRenamer); ok {
+		renames := r.EnumRenames()
+		olds := make([]string, 0, len(renames))
+		for old := range renames {
+			olds = append(olds, old)
+		}
+		sort.Strings(olds)
+		for _, old := range olds {
+			renamed := renames[old]
+			if !have[old] || have[renamed] {
+				continue
+			}
+			logconfig.Log.Info("Enum değeri yeniden adlandırılıyor", zap.String("enum", name), zap.String("from", old), zap.String("to", renamed))
+			err := tx.Exec("ALTER TYPE " + quoteIdent(name) + " RENAME VALUE " + quoteLiteral(old) + " TO " + quoteLiteral(renamed)).Error
+			if err != nil {
+				return err
+			}
+			delete(have, old)
+			have[renamed] = true
+		}
+	}
+
+	for i, v := range desired {
+		if have[v] {
+			continue
+		}
+		stmt := "ALTER TYPE " + quoteIdent(name) + " ADD VALUE IF NOT EXISTS " + quoteLiteral(v)
+		if neighbor, ok := previousPresent(desired[:i], have); ok {
+			stmt += " AFTER " + quoteLiteral(neighbor)
+		} else if neighbor, ok := nextPresent(desired[i+1:], have); ok {
+			stmt += " BEFORE " + quoteLiteral(neighbor)
+		}
+		logconfig.Log.Info("Enum değeri ekleniyor", zap.String("enum", name), zap.String("value", v))
+		if err := tx.Exec(stmt).Error; err != nil {
+			return err
+		}
+		have[v] = true
+	}
+
+	if extra := difference(keys(have), desired); len(extra) > 0 {
+		logconfig.Log.Warn("Veritabanındaki enum tipinde kodda olmayan değerler var; PostgreSQL enum değeri silmeyi desteklemez",
+			zap.String("enum", name), zap.Strings("values", extra))
+	}
+	return nil
+}
+
+// Validate veritabanındaki enum tiplerinin koddaki değerlerin tamamını içerdiğini
+// doğrular. Eksik tip ya da değer hata, kodda olmayan fazladan değerler uyarıdır.
+func Validate(db *gorm.DB, enums ...models.Enum) error {
+	if db.Dialector.Name() != "postgres" {
+		return nil
+	}
+
+	var problems []string
+	for _, e := range enums {
+		name := e.EnumName()
+		exists, err := typeExists(db, name)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			problems = append(problems, name+" tipi yok")
+			continue
+		}
+		current, err := labels(db, name)
+		if err != nil {
+			return err
+		}
+		if missing := difference(e.EnumValues(), current); len(missing) > 0 {
+			problems = append(problems, name+" tipinde eksik değerler: "+strings.Join(missing, ", "))
+		}
+		if extra := difference(current, e.EnumValues()); len(extra) > 0 {
+			logconfig.Log.Warn("Veritabanındaki enum tipinde kodda olmayan değerler var",
+				zap.String("enum", name), zap.Strings("values", extra))
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New("veritabanı enum tipleri kodla uyuşmuyor: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func typeExists(db *gorm.DB, name string) (bool, error) {
+	var count int64
+	err := db.Raw(`SELECT COUNT(*) FROM pg_type t
+		JOIN pg_namespace n ON n.oid = t.typnamespace
+		WHERE t.typname = ? AND t.typtype = 'e' AND n.nspname = current_schema()`, name).Scan(&count).Error
+	return count > 0, err
+}
+
+func labels(db *gorm.DB, name string) ([]string, error) {
+	var values []string
+	err := db.Raw(`SELECT e.enumlabel FROM pg_enum e
+		JOIN pg_type t ON t.oid = e.enumtypid
+		JOIN pg_namespace n ON n.oid = t.typnamespace
+		WHERE t.typname = ? AND n.nspname = current_schema()
+		ORDER BY e.enumsortorder`, name).Scan(&values).Error
+	return values, err
+}
+
+func previousPresent(values []string, have map[string]bool) (string, bool) {
+	for i := len(values) - 1; i >= 0; i-- {
+		if have[values[i]] {
+			return values[i], true
+		}
+	}
+	return "", false
+}
+
+func nextPresent(values []string, have map[string]bool) (string, bool) {
+	for _, v := range values {
+		if have[v] {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// difference a'da olup b'de olmayan değerleri a'daki sırayla döner.
+func difference(a, b []string) []string {
+	inB := make(map[string]bool, len(b))
+	for _, v := range b {
+		inB[v] = true
+	}
+	var out []string
+	for _, v := range a {
+		if !inB[v] {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func keys(m map[string]bool) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// DDL komutları parametre kabul etmediği için değerler literal olarak yazılır.
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
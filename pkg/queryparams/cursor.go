@@ -0,0 +1,106 @@
+package queryparams
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"zatrano/pkg/apperrors"
+)
+
+const PaginationCursor = "cursor"
+
+var ErrInvalidCursor = apperrors.Validation("geçersiz sayfa imleci", "cursor")
+
+type CursorMeta struct {
+	PerPage    int    `json:"per_page"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+type CursorResult struct {
+	Data        interface{} `json:"data"`
+	Meta        CursorMeta  `json:"meta"`
+	Suggestions []string    `json:"suggestions,omitempty"`
+}
+
+// Cursor bir sayfanın sınırındaki kaydın sıralama değeri ile ID'sidir. Backward,
+// imlecin önceki sayfaya doğru okunacağını belirtir.
+type Cursor struct {
+	SortBy   string
+	OrderBy  string
+	Value    interface{}
+	ID       uint
+	Backward bool
+}
+
+type cursorPayload struct {
+	SortBy   string      `json:"s"`
+	OrderBy  string      `json:"o"`
+	Kind     string      `json:"k,omitempty"`
+	Value    interface{} `json:"v"`
+	ID       uint        `json:"i"`
+	Backward bool        `json:"b,omitempty"`
+}
+
+const cursorKindTime = "time"
+
+// EncodeCursor imleci istemciye verilecek opak bir dizeye çevirir.
+func EncodeCursor(c Cursor) (string, error) {
+	payload := cursorPayload{SortBy: c.SortBy, OrderBy: c.OrderBy, Value: c.Value, ID: c.ID, Backward: c.Backward}
+	if t, ok := c.Value.(time.Time); ok {
+		payload.Kind = cursorKindTime
+		payload.Value = t.Format(time.RFC3339Nano)
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func DecodeCursor(token string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	var payload cursorPayload
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&payload); err != nil || payload.SortBy == "" || payload.ID == 0 {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	value := payload.Value
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			value = i
+		} else if f, err := v.Float64(); err == nil {
+			value = f
+		} else {
+			return Cursor{}, ErrInvalidCursor
+		}
+	case string:
+		if payload.Kind == cursorKindTime {
+			t, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				return Cursor{}, ErrInvalidCursor
+			}
+			value = t
+		}
+	case nil, map[string]interface{}, []interface{}:
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return Cursor{
+		SortBy:   payload.SortBy,
+		OrderBy:  payload.OrderBy,
+		Value:    value,
+		ID:       payload.ID,
+		Backward: payload.Backward,
+	}, nil
+}
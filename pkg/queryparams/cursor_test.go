@@ -0,0 +1,79 @@
+package queryparams
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	created := time.Date(2025, 1, 31, 14, 30, 15, 123456789, time.FixedZone("TRT", 3*60*60))
+
+	tests := []struct {
+		name   string
+		cursor Cursor
+	}{
+		{name: "metin", cursor: Cursor{SortBy: "name", OrderBy: "asc", Value: "Işık", ID: 7}},
+		{name: "tam sayı", cursor: Cursor{SortBy: "id", OrderBy: "desc", Value: int64(42), ID: 42}},
+		{name: "ondalıklı sayı", cursor: Cursor{SortBy: "price", OrderBy: "asc", Value: 12.5, ID: 3}},
+		{name: "bool", cursor: Cursor{SortBy: "status", OrderBy: "asc", Value: true, ID: 9}},
+		{name: "zaman", cursor: Cursor{SortBy: "created_at", OrderBy: "desc", Value: created, ID: 11}},
+		{name: "geriye doğru", cursor: Cursor{SortBy: "id", OrderBy: "asc", Value: int64(5), ID: 5, Backward: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := EncodeCursor(tt.cursor)
+			if err != nil {
+				t.Fatalf("EncodeCursor() hata = %v", err)
+			}
+			got, err := DecodeCursor(token)
+			if err != nil {
+				t.Fatalf("DecodeCursor() hata = %v", err)
+			}
+
+			if want, ok := tt.cursor.Value.(time.Time); ok {
+				value, ok := got.Value.(time.Time)
+				if !ok || !value.Equal(want) {
+					t.Fatalf("Value = %#v, want %v", got.Value, want)
+				}
+				got.Value, tt.cursor.Value = nil, nil
+			}
+			if !reflect.DeepEqual(got, tt.cursor) {
+				t.Errorf("DecodeCursor() = %#v, want %#v", got, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorRejects(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "boş", token: ""},
+		{name: "base64 değil", token: "***"},
+		{name: "json değil", token: encode("imlec")},
+		{name: "sıralama alanı yok", token: encode(`{"o":"asc","v":1,"i":1}`)},
+		{name: "id yok", token: encode(`{"s":"id","o":"asc","v":1}`)},
+		{name: "negatif id", token: encode(`{"s":"id","o":"asc","v":1,"i":-1}`)},
+		{name: "değer yok", token: encode(`{"s":"id","o":"asc","i":1}`)},
+		{name: "nesne değer", token: encode(`{"s":"id","o":"asc","v":{"a":1},"i":1}`)},
+		{name: "dizi değer", token: encode(`{"s":"id","o":"asc","v":[1],"i":1}`)},
+		{name: "geçersiz zaman", token: encode(`{"s":"created_at","o":"asc","k":"time","v":"dün","i":1}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeCursor(tt.token); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("DecodeCursor() hata = %v, want %v", err, ErrInvalidCursor)
+			}
+		})
+	}
+}
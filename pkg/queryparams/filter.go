@@ -0,0 +1,130 @@
+package queryparams
+
+import (
+	"sort"
+	"strings"
+
+	"zatrano/pkg/apperrors"
+)
+
+type Operator string
+
+const (
+	OpEq       Operator = "eq"
+	OpNe       Operator = "ne"
+	OpIn       Operator = "in"
+	OpGt       Operator = "gt"
+	OpGte      Operator = "gte"
+	OpLt       Operator = "lt"
+	OpLte      Operator = "lte"
+	OpBetween  Operator = "between"
+	OpContains Operator = "contains"
+	OpNull     Operator = "null"
+)
+
+var knownOperators = map[Operator]bool{
+	OpEq: true, OpNe: true, OpIn: true,
+	OpGt: true, OpGte: true, OpLt: true, OpLte: true,
+	OpBetween: true, OpContains: true, OpNull: true,
+}
+
+// ValueSeparator "in" ve "between" filtrelerinde değerleri ayırır: type[in]=panel,dashboard
+const ValueSeparator = ","
+
+// reservedKeys filtre olarak yorumlanmayan liste parametreleridir.
+var reservedKeys = map[string]bool{
+	"page":       true,
+	"perPage":    true,
+	"sortBy":     true,
+	"orderBy":    true,
+	"pagination": true,
+	"cursor":     true,
+	"q":          true,
+}
+
+// Filter sorgu dizesinden okunan tek bir koşuldur. Operator boşsa alan yalın
+// yazılmıştır (name=ali) ve şemada tanımlı varsayılan operatör uygulanır.
+type Filter struct {
+	Field    string
+	Operator Operator
+	Values   []string
+}
+
+// Key filtrenin sorgu dizesindeki anahtarını döner: "name" ya da "created_at[gte]".
+func (f Filter) Key() string {
+	if f.Operator == "" {
+		return f.Field
+	}
+	return f.Field + "[" + string(f.Operator) + "]"
+}
+
+func (f Filter) Value() string {
+	return strings.Join(f.Values, ValueSeparator)
+}
+
+// ParseFilters "alan" ve "alan[operatör]" biçimindeki sorgu parametrelerini filtrelere
+// çevirir. Sayfalama ve sıralama anahtarları ile extraReserved içindekiler atlanır,
+// boş değerler yok sayılır. Alanların geçerliliği repository şemasında denetlenir.
+func ParseFilters(query map[string]string, extraReserved ...string) ([]Filter, error) {
+	skip := make(map[string]bool, len(extraReserved))
+	for _, key := range extraReserved {
+		skip[key] = true
+	}
+
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var filters []Filter
+	for _, key := range keys {
+		if reservedKeys[key] || skip[key] {
+			continue
+		}
+		raw := strings.TrimSpace(query[key])
+		if raw == "" {
+			continue
+		}
+
+		field, op, err := parseFilterKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		values := []string{raw}
+		if op == OpIn || op == OpBetween {
+			values = splitValues(raw)
+		}
+		filters = append(filters, Filter{Field: field, Operator: op, Values: values})
+	}
+	return filters, nil
+}
+
+func parseFilterKey(key string) (string, Operator, error) {
+	open := strings.IndexByte(key, '[')
+	if open < 0 {
+		return key, "", nil
+	}
+	if open == 0 || !strings.HasSuffix(key, "]") {
+		return "", "", apperrors.Validation("geçersiz filtre parametresi: " + key)
+	}
+
+	field := key[:open]
+	op := Operator(key[open+1 : len(key)-1])
+	if !knownOperators[op] {
+		return "", "", apperrors.Validation("desteklenmeyen filtre operatörü: "+string(op), field)
+	}
+	return field, op, nil
+}
+
+func splitValues(raw string) []string {
+	parts := strings.Split(raw, ValueSeparator)
+	values := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
@@ -0,0 +1,120 @@
+package queryparams
+
+import (
+	"reflect"
+	"testing"
+
+	"zatrano/pkg/apperrors"
+)
+
+func TestParseFilters(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    map[string]string
+		reserved []string
+		want     []Filter
+	}{
+		{
+			name:  "yalın alan",
+			query: map[string]string{"name": "ali"},
+			want:  []Filter{{Field: "name", Values: []string{"ali"}}},
+		},
+		{
+			name:  "operatörlü alan",
+			query: map[string]string{"created_at[gte]": "2025-01-01"},
+			want:  []Filter{{Field: "created_at", Operator: OpGte, Values: []string{"2025-01-01"}}},
+		},
+		{
+			name:  "in değerleri ayrılır, boşlar atılır",
+			query: map[string]string{"type[in]": " panel, ,dashboard "},
+			want:  []Filter{{Field: "type", Operator: OpIn, Values: []string{"panel", "dashboard"}}},
+		},
+		{
+			name:  "between iki değer",
+			query: map[string]string{"id[between]": "1,10"},
+			want:  []Filter{{Field: "id", Operator: OpBetween, Values: []string{"1", "10"}}},
+		},
+		{
+			name:  "contains değeri virgülle bölünmez",
+			query: map[string]string{"name[contains]": "ali, veli"},
+			want:  []Filter{{Field: "name", Operator: OpContains, Values: []string{"ali, veli"}}},
+		},
+		{
+			name: "sayfalama, sıralama ve ek ayrılmış anahtarlar atlanır",
+			query: map[string]string{
+				"page": "2", "perPage": "20", "sortBy": "id", "orderBy": "asc",
+				"pagination": "cursor", "cursor": "abc", "q": "ali", "format": "csv",
+				"status": "true",
+			},
+			reserved: []string{"format"},
+			want:     []Filter{{Field: "status", Values: []string{"true"}}},
+		},
+		{
+			name:  "boş değerler yok sayılır",
+			query: map[string]string{"name": "  ", "email[null]": ""},
+			want:  nil,
+		},
+		{
+			name:  "anahtar sırasıyla döner",
+			query: map[string]string{"type": "panel", "account": "a", "name": "b"},
+			want: []Filter{
+				{Field: "account", Values: []string{"a"}},
+				{Field: "name", Values: []string{"b"}},
+				{Field: "type", Values: []string{"panel"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFilters(tt.query, tt.reserved...)
+			if err != nil {
+				t.Fatalf("beklenmeyen hata: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFilters() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFiltersRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		query map[string]string
+	}{
+		{name: "bilinmeyen operatör", query: map[string]string{"name[like]": "ali"}},
+		{name: "alan adı yok", query: map[string]string{"[eq]": "ali"}},
+		{name: "kapanmayan köşeli parantez", query: map[string]string{"name[eq": "ali"}},
+		{name: "boş operatör", query: map[string]string{"name[]": "ali"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseFilters(tt.query)
+			if !apperrors.IsKind(err, apperrors.KindValidation) {
+				t.Errorf("ParseFilters() hata = %v, doğrulama hatası bekleniyordu", err)
+			}
+		})
+	}
+}
+
+func TestFilterKey(t *testing.T) {
+	tests := []struct {
+		filter Filter
+		key    string
+		value  string
+	}{
+		{filter: Filter{Field: "name", Values: []string{"ali"}}, key: "name", value: "ali"},
+		{filter: Filter{Field: "type", Operator: OpIn, Values: []string{"panel", "dashboard"}}, key: "type[in]", value: "panel,dashboard"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.filter.Key(); got != tt.key {
+			t.Errorf("Key() = %q, want %q", got, tt.key)
+		}
+		if got := tt.filter.Value(); got != tt.value {
+			t.Errorf("Value() = %q, want %q", got, tt.value)
+		}
+	}
+}
@@ -11,15 +11,21 @@ const (
 )
 
 type ListParams struct {
-	Name   string `query:"name"`
-	Type   string `query:"type"`
-	Status string `query:"status"`
+	Filters []Filter `query:"-"`
+
+	// Search modelin tam metin arama alanlarında aranır.
+	Search string `query:"q"`
 
 	SortBy  string `query:"sortBy"`
 	OrderBy string `query:"orderBy"`
 
 	Page    int `query:"page"`
 	PerPage int `query:"perPage"`
+
+	// Pagination "cursor" olduğunda ya da Cursor doluysa sayfalama OFFSET yerine
+	// imleçle yapılır ve toplam kayıt sayısı hesaplanmaz.
+	Pagination string `query:"pagination"`
+	Cursor     string `query:"cursor"`
 }
 
 type PaginationMeta struct {
@@ -30,8 +36,13 @@ type PaginationMeta struct {
 }
 
 type PaginatedResult struct {
-	Data interface{}    `json:"data"`
-	Meta PaginationMeta `json:"meta"`
+	Data        interface{}    `json:"data"`
+	Meta        PaginationMeta `json:"meta"`
+	Suggestions []string       `json:"suggestions,omitempty"`
+}
+
+func (p ListParams) UsesCursor() bool {
+	return p.Pagination == PaginationCursor || p.Cursor != ""
 }
 
 func (p *ListParams) CalculateOffset() int {
@@ -41,6 +52,17 @@ func (p *ListParams) CalculateOffset() int {
 	return (p.Page - 1) * p.PerPage
 }
 
+// FilterValue alanın yalın yazılmış (name=ali) filtre değerini döner; form
+// alanlarını doldurmak için kullanılır.
+func (p ListParams) FilterValue(field string) string {
+	for _, f := range p.Filters {
+		if f.Field == field && f.Operator == "" {
+			return f.Value()
+		}
+	}
+	return ""
+}
+
 func CalculateTotalPages(totalItems int64, perPage int) int {
 	if perPage <= 0 {
 		return 1
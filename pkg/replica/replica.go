@@ -0,0 +1,176 @@
+package replica
+
+import (
+	"context"
+	"database/sql"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	"zatrano/configs/envconfig"
+	"zatrano/configs/logconfig"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+const pingTimeout = 2 * time.Second
+
+// Monitor okuma kopyalarının erişilebilirliğini periyodik olarak kontrol eder.
+type Monitor struct {
+	pools   []*sql.DB
+	healthy []atomic.Bool
+}
+
+var current atomic.Pointer[Monitor]
+
+func NewMonitor(pools []*sql.DB) *Monitor {
+	return &Monitor{pools: pools, healthy: make([]atomic.Bool, len(pools))}
+}
+
+// Start kopyaları hemen bir kez kontrol eder, ardından interval aralıklarla kontrolü
+// arka planda sürdürür ve monitörü paket genelinde etkinleştirir.
+func (m *Monitor) Start(interval time.Duration) {
+	m.check()
+	current.Store(m)
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			m.check()
+		}
+	}()
+}
+
+func (m *Monitor) check() {
+	for i, pool := range m.pools {
+		ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+		err := pool.PingContext(ctx)
+		cancel()
+
+		healthy := err == nil
+		if m.healthy[i].Swap(healthy) != healthy {
+			if healthy {
+				logconfig.Log.Info("Okuma kopyası erişilebilir", zap.Int("replica", i))
+			} else {
+				logconfig.Log.Warn("Okuma kopyasına erişilemiyor, okumalar diğer kopyalara veya birincil veritabanına yönlendirilecek",
+					zap.Int("replica", i), zap.Error(err))
+			}
+		}
+	}
+}
+
+// Available en az bir kopyanın erişilebilir olduğunu bildirir.
+func (m *Monitor) Available() bool {
+	for i := range m.healthy {
+		if m.healthy[i].Load() {
+			return true
+		}
+	}
+	return false
+}
+
+// Policy yalnızca erişilebilir kopyalar arasından rastgele seçim yapar. Hiçbiri
+// erişilebilir değilse okumalar zaten birincil veritabanına yönlendirildiği için
+// ilk kopya döner.
+func (m *Monitor) Policy() dbresolver.Policy {
+	return dbresolver.PolicyFunc(func(connPools []gorm.ConnPool) gorm.ConnPool {
+		var candidates []gorm.ConnPool
+		for _, pool := range connPools {
+			if m.isHealthy(pool) {
+				candidates = append(candidates, pool)
+			}
+		}
+		if len(candidates) == 0 {
+			return connPools[0]
+		}
+		return candidates[rand.IntN(len(candidates))]
+	})
+}
+
+func (m *Monitor) isHealthy(pool gorm.ConnPool) bool {
+	for i, p := range m.pools {
+		if gorm.ConnPool(p) == pool {
+			return m.healthy[i].Load()
+		}
+	}
+	return false
+}
+
+// Enabled okuma kopyalarının yapılandırıldığını bildirir.
+func Enabled() bool {
+	return current.Load() != nil
+}
+
+// ReadYourWritesWindow, bir istekte yazma yapıldıktan sonra aynı istemcinin
+// okumalarının birincil veritabanından yapılacağı süredir.
+func ReadYourWritesWindow() time.Duration {
+	return time.Duration(envconfig.GetEnvAsInt("DB_REPLICA_READ_YOUR_WRITES_SECONDS", 5)) * time.Second
+}
+
+type requestState struct {
+	primaryOnly bool
+	wrote       atomic.Bool
+}
+
+type stateKey struct{}
+
+// WithRequest isteğin yazma yapıp yapmadığını izlemek için context'e durum ekler.
+// primaryOnly, istemcinin yakın zamanda yazma yaptığını ve kopyaların henüz geride
+// olabileceğini belirtir.
+func WithRequest(ctx context.Context, primaryOnly bool) context.Context {
+	return context.WithValue(ctx, stateKey{}, &requestState{primaryOnly: primaryOnly})
+}
+
+func state(ctx context.Context) *requestState {
+	s, _ := ctx.Value(stateKey{}).(*requestState)
+	return s
+}
+
+// Wrote istek boyunca en az bir yazma yapıldığını bildirir.
+func Wrote(ctx context.Context) bool {
+	s := state(ctx)
+	return s != nil && s.wrote.Load()
+}
+
+// Mode okumanın hangi veritabanından yapılacağını belirten dbresolver clause'unu
+// döner. Kopyalar yapılandırılmamışsa, hiçbiri erişilebilir değilse ya da istemci
+// yakın zamanda yazma yaptıysa birincil veritabanı seçilir.
+func Mode(ctx context.Context) dbresolver.Operation {
+	m := current.Load()
+	if m == nil || !m.Available() {
+		return dbresolver.Write
+	}
+	if s := state(ctx); s != nil && (s.primaryOnly || s.wrote.Load()) {
+		return dbresolver.Write
+	}
+	return dbresolver.Read
+}
+
+// RegisterWriteTracking başarılı her yazmayı isteğin context'ine işler.
+func RegisterWriteTracking(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := cb.Create().After("gorm:create").Register("replica:track_create", markWrite); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:update").Register("replica:track_update", markWrite); err != nil {
+		return err
+	}
+	if err := cb.Delete().After("gorm:delete").Register("replica:track_delete", markWrite); err != nil {
+		return err
+	}
+	return cb.Raw().After("gorm:raw").Register("replica:track_raw", markWrite)
+}
+
+func markWrite(db *gorm.DB) {
+	if db.Error != nil || db.Statement.RowsAffected == 0 {
+		return
+	}
+	if s := state(db.Statement.Context); s != nil {
+		s.wrote.Store(true)
+	}
+}
@@ -0,0 +1,93 @@
+package signedtoken
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"zatrano/configs/envconfig"
+	"zatrano/configs/logconfig"
+)
+
+var (
+	ErrInvalidToken = errors.New("geçersiz bağlantı")
+	ErrExpiredToken = errors.New("bağlantının süresi dolmuş")
+)
+
+const developmentKey = "zatrano-development-key"
+
+type claims struct {
+	Purpose   string `json:"p"`
+	Subject   uint   `json:"s"`
+	Nonce     string `json:"n"`
+	ExpiresAt int64  `json:"e"`
+}
+
+func secret() []byte {
+	key := envconfig.GetEnvWithDefault("APP_KEY", "")
+	if key == "" {
+		if envconfig.IsProduction() {
+			logconfig.SLog.Fatal("APP_KEY ortam değişkeni production ortamında zorunludur")
+		}
+		key = developmentKey
+	}
+	return []byte(key)
+}
+
+func Sign(purpose string, subject uint, expiresAt time.Time) (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(claims{
+		Purpose:   purpose,
+		Subject:   subject,
+		Nonce:     hex.EncodeToString(nonce),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + signature(encoded), nil
+}
+
+func Verify(purpose, token string) (uint, error) {
+	encoded, sig, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(signature(encoded))) {
+		return 0, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+
+	var c claims
+	if err := json.Unmarshal(payload, &c); err != nil || c.Purpose != purpose || c.Subject == 0 {
+		return 0, ErrInvalidToken
+	}
+	if time.Now().Unix() > c.ExpiresAt {
+		return c.Subject, ErrExpiredToken
+	}
+	return c.Subject, nil
+}
+
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func signature(encoded string) string {
+	mac := hmac.New(sha256.New, secret())
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -1,6 +1,8 @@
 package templatehelpers
 
 import (
+	"zatrano/pkg/fulltext"
+
 	"net/url"
 	"text/template"
 	"time"
@@ -35,7 +37,14 @@ func TemplateHelpers() template.FuncMap {
 			}
 			return items
 		},
-		"urlquery": func(s string) string { return url.QueryEscape(s) },
+		"urlquery":  func(s string) string { return url.QueryEscape(s) },
+		"Highlight": fulltext.HighlightHTML,
+		"deref": func(p *uint) uint {
+			if p == nil {
+				return 0
+			}
+			return *p
+		},
 		"dict": func(values ...interface{}) map[string]interface{} {
 			dict := make(map[string]interface{})
 			if len(values)%2 != 0 {
@@ -0,0 +1,37 @@
+package tenancy
+
+import "context"
+
+const (
+	contextTenantIDKey = "tenant_id"
+	contextBypassKey   = "tenant_bypass"
+)
+
+func WithTenant(ctx context.Context, tenantID uint) context.Context {
+	return context.WithValue(ctx, contextTenantIDKey, tenantID)
+}
+
+func TenantID(ctx context.Context) (uint, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	tenantID, ok := ctx.Value(contextTenantIDKey).(uint)
+	if !ok || tenantID == 0 {
+		return 0, false
+	}
+	return tenantID, true
+}
+
+// WithBypass süper yöneticilerin kiracılar arası yönetim yapabilmesi için
+// repository katmanındaki otomatik kiracı filtresini devre dışı bırakır.
+func WithBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextBypassKey, true)
+}
+
+func IsBypassed(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	bypass, _ := ctx.Value(contextBypassKey).(bool)
+	return bypass
+}
@@ -5,39 +5,52 @@ import (
 	"unicode"
 )
 
-func normalize(str string) string {
-	replacements := map[rune]rune{
-		'ç': 'c', 'Ç': 'C',
-		'ğ': 'g', 'Ğ': 'G',
-		'ı': 'i', 'I': 'I',
-		'ö': 'o', 'Ö': 'O',
-		'ş': 's', 'Ş': 'S',
-		'ü': 'u', 'Ü': 'U',
-	}
+// foldPairs Türkçe harfleri ve şapkalı ünlüleri ASCII karşılıklarına eşler. Aynı tablo
+// SQL tarafındaki translate() ifadesinde de kullanılır; iki taraf aynı sonucu üretir.
+var foldPairs = [][2]rune{
+	{'Ç', 'c'}, {'Ğ', 'g'}, {'I', 'i'}, {'İ', 'i'}, {'Ö', 'o'}, {'Ş', 's'}, {'Ü', 'u'},
+	{'Â', 'a'}, {'Î', 'i'}, {'Û', 'u'},
+	{'ç', 'c'}, {'ğ', 'g'}, {'ı', 'i'}, {'ö', 'o'}, {'ş', 's'}, {'ü', 'u'},
+	{'â', 'a'}, {'î', 'i'}, {'û', 'u'},
+}
 
-	var builder strings.Builder
-	for _, r := range str {
-		if repl, ok := replacements[r]; ok {
-			builder.WriteRune(unicode.ToLower(repl))
-		} else {
-			builder.WriteRune(unicode.ToLower(r))
-		}
+var foldMap = func() map[rune]rune {
+	m := make(map[rune]rune, len(foldPairs))
+	for _, p := range foldPairs {
+		m[p[0]] = p[1]
 	}
-	return builder.String()
-}
+	return m
+}()
 
-func MatchNormalized(text, keyword string) bool {
-	normText := normalize(text)
-	normKeyword := normalize(keyword)
-	return strings.Contains(normText, normKeyword)
-}
+// combiningDotAbove, "İ" harfinin Türkçe dışı küçültülmesinde oluşan "i̇" dizisindeki
+// birleşik noktadır; katlamada atılır.
+const combiningDotAbove = '\u0307'
 
-func SQLFilter(columnName, search string) (string, []interface{}) {
-	filterValue := "%" + strings.ToLower(search) + "%"
+// ToLower Türkçe kurallarla küçültür: "I" → "ı", "İ" → "i".
+func ToLower(str string) string {
+	return strings.ToLowerSpecial(unicode.TurkishCase, str)
+}
 
-	query := "unaccent(lower(" + columnName + ")) ILIKE unaccent($1)"
+// ToUpper Türkçe kurallarla büyütür: "i" → "İ", "ı" → "I".
+func ToUpper(str string) string {
+	return strings.ToUpperSpecial(unicode.TurkishCase, str)
+}
 
-	params := []interface{}{filterValue}
+// Fold metni Türkçe karakterleri ASCII karşılıklarına indirip küçük harfe çevirir;
+// büyük/küçük harf ve aksan farkı gözetmeyen karşılaştırmalar için kullanılır.
+// "IŞIK", "ışık" ve "isik" aynı değere katlanır.
+func Fold(str string) string {
+	return strings.Map(func(r rune) rune {
+		if r == combiningDotAbove {
+			return -1
+		}
+		if folded, ok := foldMap[r]; ok {
+			return folded
+		}
+		return unicode.ToLower(r)
+	}, str)
+}
 
-	return query, params
+func MatchNormalized(text, keyword string) bool {
+	return strings.Contains(Fold(text), Fold(keyword))
 }
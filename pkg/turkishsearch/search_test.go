@@ -0,0 +1,88 @@
+package turkishsearch
+
+import "testing"
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "boş", in: "", want: ""},
+		{name: "ASCII küçültülür", in: "Ahmet YILMAZ", want: "ahmet yilmaz"},
+		{name: "noktasız büyük I", in: "IŞIK", want: "isik"},
+		{name: "noktasız küçük ı", in: "ışık", want: "isik"},
+		{name: "noktalı büyük İ", in: "İSTANBUL", want: "istanbul"},
+		{name: "Türkçe dışı küçültmedeki birleşik nokta atılır", in: "i̇stanbul", want: "istanbul"},
+		{name: "tüm Türkçe harfler", in: "ÇĞİÖŞÜçğıöşü", want: "cgiosucgiosu"},
+		{name: "şapkalı ünlüler", in: "Kâtip Îmâ Ûmumi", want: "katip ima umumi"},
+		{name: "rakam ve noktalama korunur", in: "Şube-12, Kat:3", want: "sube-12, kat:3"},
+		{name: "diğer dillerdeki harfler yalnızca küçültülür", in: "ÉLAN Straße", want: "élan straße"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Fold(tt.in); got != tt.want {
+				t.Errorf("Fold(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFoldEquivalence(t *testing.T) {
+	groups := [][]string{
+		{"IŞIK", "ışık", "Işık", "isik", "ISIK"},
+		{"ÇİĞDEM", "çiğdem", "Cigdem"},
+		{"Üsküdar", "USKUDAR", "üsküdar"},
+	}
+
+	for _, group := range groups {
+		want := Fold(group[0])
+		for _, s := range group[1:] {
+			if got := Fold(s); got != want {
+				t.Errorf("Fold(%q) = %q, Fold(%q) = %q; aynı olmalıydı", s, got, group[0], want)
+			}
+		}
+	}
+}
+
+func TestTurkishCase(t *testing.T) {
+	tests := []struct {
+		in    string
+		lower string
+		upper string
+	}{
+		{in: "Işık", lower: "ışık", upper: "IŞIK"},
+		{in: "istanbul", lower: "istanbul", upper: "İSTANBUL"},
+		{in: "İZMİR", lower: "izmir", upper: "İZMİR"},
+	}
+
+	for _, tt := range tests {
+		if got := ToLower(tt.in); got != tt.lower {
+			t.Errorf("ToLower(%q) = %q, want %q", tt.in, got, tt.lower)
+		}
+		if got := ToUpper(tt.in); got != tt.upper {
+			t.Errorf("ToUpper(%q) = %q, want %q", tt.in, got, tt.upper)
+		}
+	}
+}
+
+func TestMatchNormalized(t *testing.T) {
+	tests := []struct {
+		text    string
+		keyword string
+		want    bool
+	}{
+		{text: "Işık Mahallesi", keyword: "isik", want: true},
+		{text: "ÇİĞDEM SOKAK", keyword: "çiğdem", want: true},
+		{text: "Üsküdar", keyword: "SKUD", want: true},
+		{text: "Kadıköy", keyword: "", want: true},
+		{text: "Kadıköy", keyword: "beşiktaş", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchNormalized(tt.text, tt.keyword); got != tt.want {
+			t.Errorf("MatchNormalized(%q, %q) = %v, want %v", tt.text, tt.keyword, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package turkishsearch
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"zatrano/configs/envconfig"
+
+	"gorm.io/gorm"
+)
+
+type capabilities struct {
+	dialect  string
+	unaccent bool
+	trigram  bool
+}
+
+var current atomic.Pointer[capabilities]
+
+func init() {
+	current.Store(&capabilities{dialect: "postgres"})
+}
+
+// Detect veritabanı sürücüsünü ve unaccent / pg_trgm eklentilerinin varlığını okur.
+// Bağlantı kurulduktan sonra bir kez çağrılır; çağrılmazsa eklentisiz PostgreSQL
+// varsayılır ve katlama yalnızca translate() ile yapılır.
+func Detect(db *gorm.DB) error {
+	caps := &capabilities{dialect: db.Dialector.Name()}
+	if caps.dialect == "postgres" {
+		var extensions []string
+		err := db.Raw("SELECT extname FROM pg_extension WHERE extname IN ('unaccent', 'pg_trgm')").Scan(&extensions).Error
+		if err != nil {
+			return err
+		}
+		for _, ext := range extensions {
+			switch ext {
+			case "unaccent":
+				caps.unaccent = true
+			case "pg_trgm":
+				caps.trigram = true
+			}
+		}
+	}
+	current.Store(caps)
+	return nil
+}
+
+// TrigramEnabled pg_trgm ile benzerlik aramasının kullanılabildiğini bildirir.
+func TrigramEnabled() bool {
+	return current.Load().trigram
+}
+
+// FuzzyThreshold yazım hatası toleranslı eşleşme için word_similarity alt sınırıdır;
+// 0 bulanık eşleşmeyi kapatır.
+func FuzzyThreshold() float64 {
+	return envconfig.GetEnvAsFloat("SEARCH_FUZZY_THRESHOLD", 0.5)
+}
+
+// SuggestThreshold "bunu mu demek istediniz" önerileri için similarity alt sınırıdır.
+func SuggestThreshold() float64 {
+	return envconfig.GetEnvAsFloat("SEARCH_SUGGEST_THRESHOLD", 0.2)
+}
+
+// SQLFold sütunu Fold ile aynı kurallarla katlayan SQL ifadesini döner. PostgreSQL'de
+// translate() kullanılır, unaccent kuruluysa diğer aksanlar da atılır; diğer
+// sürücülerde iç içe REPLACE kullanılır.
+func SQLFold(column string) string {
+	caps := current.Load()
+	if caps.dialect != "postgres" {
+		expr := column
+		for _, p := range foldPairs {
+			expr = "REPLACE(" + expr + ", '" + string(p[0]) + "', '" + string(p[1]) + "')"
+		}
+		return "LOWER(" + expr + ")"
+	}
+
+	from, to := translateArgs()
+	expr := "lower(translate(" + column + ", '" + from + "', '" + to + "'))"
+	if caps.unaccent {
+		expr = "unaccent(" + expr + ")"
+	}
+	return expr
+}
+
+// foldParam parametre tarafında da sütunla aynı aksan temizliğini uygular.
+func foldParam() string {
+	if current.Load().unaccent {
+		return "unaccent(?)"
+	}
+	return "?"
+}
+
+// SQLFilter sütunda aramayı Türkçe harf ve büyük/küçük harf farkı gözetmeden eşleyen
+// koşulu döner. pg_trgm kuruluysa ve eşik sıfırdan büyükse yazım hatalarına karşı
+// word_similarity ile de eşleşir.
+func SQLFilter(columnName, search string) (string, []interface{}) {
+	folded := Fold(strings.TrimSpace(search))
+	pattern := "%" + escapeLike(folded) + "%"
+
+	query := SQLFold(columnName) + " LIKE " + foldParam() + " ESCAPE '!'"
+	params := []interface{}{pattern}
+
+	if threshold := FuzzyThreshold(); threshold > 0 && TrigramEnabled() {
+		query = "(" + query + " OR word_similarity(" + foldParam() + ", " + SQLFold(columnName) + ") >= ?)"
+		params = append(params, folded, threshold)
+	}
+	return query, params
+}
+
+// SimilarityExpression sütun ile aranan metin arasındaki pg_trgm benzerliğini veren
+// ifadedir; tek parametre olarak Fold edilmiş arama metnini bekler.
+func SimilarityExpression(columnName string) string {
+	return "similarity(" + SQLFold(columnName) + ", " + foldParam() + ")"
+}
+
+func translateArgs() (string, string) {
+	var from, to strings.Builder
+	for _, p := range foldPairs {
+		from.WriteRune(p[0])
+		to.WriteRune(p[1])
+	}
+	return from.String(), to.String()
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(value)
+}
@@ -0,0 +1,133 @@
+package txmanager
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"zatrano/configs/envconfig"
+	"zatrano/configs/logconfig"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+type txKey struct{}
+
+// Serileştirme hatası ve kilitlenme: işlem baştan çalıştırılırsa başarılı olabilir.
+const (
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+	mysqlDeadlock          = 1213
+)
+
+// WithTx işleme bağlı bağlantıyı context'e koyar; repository'ler bu context ile
+// yaptıkları sorguları aynı işlem içinde çalıştırır.
+func WithTx(ctx context.Context, tx *gorm.DB) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+func FromContext(ctx context.Context) (*gorm.DB, bool) {
+	tx, ok := ctx.Value(txKey{}).(*gorm.DB)
+	return tx, ok && tx != nil
+}
+
+// DB context'te açık bir işlem varsa onu, yoksa verilen bağlantıyı ctx ile döner.
+// Okuma kopyaları yapılandırılmış olsa da sorgular varsayılan olarak birincil
+// veritabanında çalışır; kopyadan okumak için dbresolver.Read açıkça eklenmelidir.
+func DB(ctx context.Context, fallback *gorm.DB) *gorm.DB {
+	if tx, ok := FromContext(ctx); ok {
+		return tx.WithContext(ctx)
+	}
+	return fallback.WithContext(ctx).Clauses(dbresolver.Write)
+}
+
+type Manager struct {
+	db               *gorm.DB
+	maxRetries       int
+	backoff          time.Duration
+	statementTimeout time.Duration
+}
+
+func New(db *gorm.DB) *Manager {
+	return &Manager{
+		db:               db,
+		maxRetries:       envconfig.GetEnvAsInt("DB_TX_MAX_RETRIES", 3),
+		backoff:          time.Duration(envconfig.GetEnvAsInt("DB_TX_RETRY_BACKOFF_MS", 50)) * time.Millisecond,
+		statementTimeout: time.Duration(envconfig.GetEnvAsInt("DB_STATEMENT_TIMEOUT_MS", 15000)) * time.Millisecond,
+	}
+}
+
+// WithinTx fn'i tek bir işlem içinde çalıştırır; fn nil dışında bir hata dönerse işlem
+// geri alınır. Context'te zaten bir işlem varsa savepoint açılır ve yalnızca iç kısım
+// geri alınır. En dıştaki işlem serileştirme hatası veya kilitlenme ile biterse baştan
+// denenir; bu yüzden fn e-posta gönderimi gibi geri alınamayan yan etkiler içermemelidir.
+func (m *Manager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if tx, ok := FromContext(ctx); ok {
+		return tx.WithContext(ctx).Transaction(func(nested *gorm.DB) error {
+			return fn(WithTx(ctx, nested))
+		})
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := m.setStatementTimeout(ctx, tx); err != nil {
+				return err
+			}
+			return fn(WithTx(ctx, tx))
+		})
+		if err == nil || !IsRetryable(err) || attempt >= m.maxRetries {
+			return err
+		}
+
+		wait := m.backoff*time.Duration(1<<attempt) + time.Duration(rand.Int64N(int64(m.backoff)+1))
+		logconfig.Log.Warn("İşlem çakışma nedeniyle yeniden deneniyor",
+			zap.Int("attempt", attempt+1),
+			zap.Duration("wait", wait),
+			zap.Error(err),
+		)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+	}
+}
+
+// setStatementTimeout PostgreSQL'de işlemdeki her sorgunun süresini sınırlar; context'in
+// kalan süresi yapılandırılan değerden kısaysa o kullanılır. SET LOCAL işlem bitince geçersizleşir.
+func (m *Manager) setStatementTimeout(ctx context.Context, tx *gorm.DB) error {
+	if tx.Dialector.Name() != "postgres" {
+		return nil
+	}
+	timeout := m.statementTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); timeout <= 0 || remaining < timeout {
+			timeout = remaining
+		}
+	}
+	if timeout <= 0 {
+		return nil
+	}
+	ms := max(timeout.Milliseconds(), 1)
+	return tx.Exec("SET LOCAL statement_timeout = " + strconv.FormatInt(ms, 10)).Error
+}
+
+// IsRetryable hatanın işlemin yeniden denenmesiyle çözülebilecek bir çakışma olup
+// olmadığını bildirir.
+func IsRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == pgSerializationFailure || pgErr.Code == pgDeadlockDetected
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlDeadlock
+	}
+	return false
+}
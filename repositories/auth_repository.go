@@ -1,18 +1,21 @@
 package repositories
 
 import (
+	"context"
+
 	"zatrano/configs/databaseconfig"
 	"zatrano/configs/logconfig"
 	"zatrano/models"
+	"zatrano/pkg/txmanager"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
 type IAuthRepository interface {
-	FindUserByAccount(account string) (*models.User, error)
-	FindUserByID(id uint) (*models.User, error)
-	UpdateUser(user *models.User) error
+	FindUserByAccount(ctx context.Context, account string) (*models.User, error)
+	FindUserByID(ctx context.Context, id uint) (*models.User, error)
+	UpdateUser(ctx context.Context, user *models.User) error
 }
 
 type AuthRepository struct {
@@ -27,7 +30,7 @@ func (r *AuthRepository) executeQuery(query *gorm.DB, operation string, fields .
 	if err := query.Error; err != nil {
 		fields = append(fields, zap.Error(err))
 		logconfig.Log.Error(operation+" hatası", fields...)
-		return err
+		return translateError(err)
 	}
 	return nil
 }
@@ -41,25 +44,25 @@ func (r *AuthRepository) findUser(query *gorm.DB, operation string, fields ...za
 	return &user, nil
 }
 
-func (r *AuthRepository) FindUserByAccount(account string) (*models.User, error) {
+func (r *AuthRepository) FindUserByAccount(ctx context.Context, account string) (*models.User, error) {
 	return r.findUser(
-		r.db.Where("account = ?", account),
+		txmanager.DB(ctx, r.db).Where("account_key = ?", models.AccountKey(account)),
 		"Kullanıcı sorgulama (account)",
 		zap.String("account", account),
 	)
 }
 
-func (r *AuthRepository) FindUserByID(id uint) (*models.User, error) {
+func (r *AuthRepository) FindUserByID(ctx context.Context, id uint) (*models.User, error) {
 	return r.findUser(
-		r.db.Where("id = ?", id),
+		txmanager.DB(ctx, r.db).Where("id = ?", id),
 		"Kullanıcı sorgulama (ID)",
 		zap.Uint("user_id", id),
 	)
 }
 
-func (r *AuthRepository) UpdateUser(user *models.User) error {
+func (r *AuthRepository) UpdateUser(ctx context.Context, user *models.User) error {
 	return r.executeQuery(
-		r.db.Save(user),
+		txmanager.DB(ctx, r.db).Save(user),
 		"Kullanıcı güncelleme",
 		zap.Uint("user_id", user.ID),
 		zap.String("account", user.Account),
@@ -2,46 +2,89 @@ package repositories
 
 import (
 	"context"
-	"errors"
+	"reflect"
+	"slices"
 	"strings"
 
+	"zatrano/configs/logconfig"
+	"zatrano/models"
+	"zatrano/pkg/apperrors"
+	"zatrano/pkg/fulltext"
 	"zatrano/pkg/queryparams"
-	"zatrano/pkg/turkishsearch"
+	"zatrano/pkg/replica"
+	"zatrano/pkg/txmanager"
 
+	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 const userIDKey = "user_id"
 
-var (
-	ErrNotFound      = errors.New("kayıt bulunamadı")
-	ErrMissingUserID = errors.New("context içinde geçerli user_id yok")
-)
-
 type IBaseRepository[T any] interface {
-	GetAll(params queryparams.ListParams) ([]T, int64, error)
-	GetByID(id uint) (*T, error)
+	GetAll(ctx context.Context, params queryparams.ListParams) ([]T, int64, error)
+	GetCursorPage(ctx context.Context, params queryparams.ListParams) ([]T, queryparams.CursorMeta, error)
+	Suggest(ctx context.Context, search string, limit int) ([]string, error)
+	GetByID(ctx context.Context, id uint) (*T, error)
 	Create(ctx context.Context, entity *T) error
 	BulkCreate(ctx context.Context, entities []T) error
-	Update(ctx context.Context, id uint, data map[string]interface{}, updatedBy uint) error
-	BulkUpdate(ctx context.Context, condition map[string]interface{}, data map[string]interface{}, updatedBy uint) error
+	Upsert(ctx context.Context, entity *T, opts UpsertOptions) error
+	BulkUpsert(ctx context.Context, entities []T, opts UpsertOptions) (int64, error)
+	Update(ctx context.Context, id uint, patch Patch, updatedBy uint) error
+	BulkUpdate(ctx context.Context, condition map[string]interface{}, patch Patch, updatedBy uint) (int64, error)
 	Delete(ctx context.Context, id uint) error
-	BulkDelete(ctx context.Context, condition map[string]interface{}) error
-	GetCount() (int64, error)
+	BulkDelete(ctx context.Context, condition map[string]interface{}) (int64, error)
+	Restore(ctx context.Context, id uint) error
+	GetCount(ctx context.Context) (int64, error)
+	GetFilteredCount(ctx context.Context, params queryparams.ListParams) (int64, error)
+	Stream(ctx context.Context, params queryparams.ListParams, fn func(item *T) error) error
+	InBatches(ctx context.Context, params queryparams.ListParams, opts BatchOptions, fn func(ctx context.Context, batch []T) error) error
+	Each(ctx context.Context, params queryparams.ListParams, opts BatchOptions, fn func(ctx context.Context, item *T) error) error
 }
 
 type BaseRepository[T any] struct {
 	db                 *gorm.DB
+	tx                 *txmanager.Manager
 	allowedSortColumns map[string]bool
+	filterSchema       FilterSchema
+	searchFields       []fulltext.Field
+	tenantScoped       bool
+	versioned          bool
+	ownerFilter        func(ownerIDs []uint) clause.Expression
 }
 
 func NewBaseRepository[T any](db *gorm.DB) *BaseRepository[T] {
+	var t T
+	_, tenantScoped := any(&t).(models.TenantScoped)
+	_, versioned := any(&t).(models.Versioned)
+
+	var searchFields []fulltext.Field
+	if searchable, ok := any(&t).(models.FullTextSearchable); ok {
+		searchFields = searchable.SearchFields()
+	}
+
+	var ownerFilter func(ownerIDs []uint) clause.Expression
+	if filtered, ok := any(&t).(models.OwnerFiltered); ok {
+		ownerFilter = filtered.OwnerCondition
+	} else if owned, ok := any(&t).(models.OwnerScoped); ok {
+		ownerFilter = ownerColumnFilter(owned.OwnerColumn())
+	}
+
 	return &BaseRepository[T]{
 		db: db,
+		tx: txmanager.New(db),
 		allowedSortColumns: map[string]bool{
 			"id":         true,
 			"created_at": true,
 		},
+		filterSchema: FilterSchema{
+			"id":         {Type: FilterNumber, Operators: []queryparams.Operator{queryparams.OpEq, queryparams.OpIn}},
+			"created_at": {Type: FilterDate, Operators: []queryparams.Operator{queryparams.OpEq, queryparams.OpGt, queryparams.OpGte, queryparams.OpLt, queryparams.OpLte, queryparams.OpBetween}},
+		},
+		searchFields: searchFields,
+		tenantScoped: tenantScoped,
+		versioned:    versioned,
+		ownerFilter:  ownerFilter,
 	}
 }
 
@@ -52,32 +95,34 @@ func (r *BaseRepository[T]) SetAllowedSortColumns(columns []string) {
 	}
 }
 
-func (r *BaseRepository[T]) GetAll(params queryparams.ListParams) ([]T, int64, error) {
-	var results []T
-	var totalCount int64
+func (r *BaseRepository[T]) SetFilterSchema(schema FilterSchema) {
+	r.filterSchema = schema
+}
 
+func (r *BaseRepository[T]) SetOwnerColumn(column string) {
+	r.ownerFilter = ownerColumnFilter(column)
+}
+
+func (r *BaseRepository[T]) query(ctx context.Context) *gorm.DB {
 	var t T
-	query := r.db.Model(&t)
+	return txmanager.DB(ctx, r.db).Model(&t).Scopes(r.scopes(ctx)...)
+}
 
-	if params.Name != "" {
-		sqlFragment, args := turkishsearch.SQLFilter("name", params.Name)
-		query = query.Where(sqlFragment, args...)
-	}
-	if params.Status != "" {
-		query = query.Where("status = ?", params.Status)
-	}
-	if params.Type != "" {
-		query = query.Where("type = ?", params.Type)
-	}
+// readQuery liste, sayım ve tekil okuma sorgularını uygun olduğunda okuma
+// kopyalarına yönlendirir; işlem içindeyken sorgu yine işlemin bağlantısında çalışır.
+func (r *BaseRepository[T]) readQuery(ctx context.Context) *gorm.DB {
+	return r.query(ctx).Clauses(replica.Mode(ctx))
+}
 
-	err := query.Count(&totalCount).Error
+func (r *BaseRepository[T]) applyFilters(query *gorm.DB, params queryparams.ListParams) (*gorm.DB, error) {
+	query, err := r.filterSchema.apply(query, params.Filters)
 	if err != nil {
-		return nil, 0, err
-	}
-	if totalCount == 0 {
-		return results, 0, nil
+		return nil, err
 	}
+	return r.applySearch(query, params.Search), nil
+}
 
+func (r *BaseRepository[T]) sortOrder(params queryparams.ListParams) (string, string) {
 	sortBy := params.SortBy
 	orderBy := strings.ToLower(params.OrderBy)
 	if orderBy != "asc" && orderBy != "desc" {
@@ -86,50 +131,288 @@ func (r *BaseRepository[T]) GetAll(params queryparams.ListParams) ([]T, int64, e
 	if _, ok := r.allowedSortColumns[sortBy]; !ok {
 		sortBy = queryparams.DefaultSortBy
 	}
-	query = query.Order(sortBy + " " + orderBy)
+	return sortBy, orderBy
+}
+
+func (r *BaseRepository[T]) applySort(query *gorm.DB, params queryparams.ListParams) *gorm.DB {
+	sortBy, orderBy := r.sortOrder(params)
+	return query.Order(sortBy + " " + orderBy)
+}
+
+func (r *BaseRepository[T]) GetAll(ctx context.Context, params queryparams.ListParams) ([]T, int64, error) {
+	var results []T
+	var totalCount int64
+
+	query, err := r.applyFilters(r.readQuery(ctx), params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = query.Count(&totalCount).Error
+	if err != nil {
+		return nil, 0, translateError(err)
+	}
+	if totalCount == 0 {
+		return results, 0, nil
+	}
+
+	query = r.applySort(r.applyRanking(query, params.Search), params)
 
 	offset := params.CalculateOffset()
 	query = query.Limit(params.PerPage).Offset(offset)
 
 	err = query.Find(&results).Error
-	return results, totalCount, err
+	return results, totalCount, translateError(err)
+}
+
+// GetCursorPage sayfayı OFFSET yerine sıralama sütunu ve ID üzerinden (keyset) okur;
+// COUNT sorgusu çalıştırılmaz. Sıralama sütunlarının NULL olmaması gerekir.
+func (r *BaseRepository[T]) GetCursorPage(ctx context.Context, params queryparams.ListParams) ([]T, queryparams.CursorMeta, error) {
+	meta := queryparams.CursorMeta{PerPage: params.PerPage}
+	sortBy, orderBy := r.sortOrder(params)
+
+	var cursor *queryparams.Cursor
+	if params.Cursor != "" {
+		decoded, err := queryparams.DecodeCursor(params.Cursor)
+		if err != nil {
+			return nil, meta, err
+		}
+		if decoded.SortBy != sortBy || decoded.OrderBy != orderBy {
+			return nil, meta, queryparams.ErrInvalidCursor
+		}
+		cursor = &decoded
+	}
+
+	query, err := r.applyFilters(r.readQuery(ctx), params)
+	if err != nil {
+		return nil, meta, err
+	}
+
+	backward := cursor != nil && cursor.Backward
+	descending := orderBy == "desc"
+	if backward {
+		descending = !descending
+	}
+
+	if cursor != nil {
+		op := ">"
+		if descending {
+			op = "<"
+		}
+		if sortBy == "id" {
+			query = query.Where("id "+op+" ?", cursor.ID)
+		} else {
+			query = query.Where("("+sortBy+" "+op+" ? OR ("+sortBy+" = ? AND id "+op+" ?))", cursor.Value, cursor.Value, cursor.ID)
+		}
+	}
+
+	direction := " ASC"
+	if descending {
+		direction = " DESC"
+	}
+	query = query.Order(sortBy + direction)
+	if sortBy != "id" {
+		query = query.Order("id" + direction)
+	}
+
+	var results []T
+	if err := query.Limit(params.PerPage + 1).Find(&results).Error; err != nil {
+		return nil, meta, translateError(err)
+	}
+
+	hasMore := len(results) > params.PerPage
+	if hasMore {
+		results = results[:params.PerPage]
+	}
+	if backward {
+		slices.Reverse(results)
+	}
+	if len(results) == 0 {
+		return results, meta, nil
+	}
+
+	// Geriye okunan sayfada sonraki sayfa her zaman vardır; fazladan satır ise
+	// öncesinde de kayıt olduğunu gösterir.
+	hasNext, hasPrev := hasMore, cursor != nil
+	if backward {
+		hasNext, hasPrev = true, hasMore
+	}
+	if hasNext {
+		if meta.NextCursor, err = r.encodeCursor(ctx, &results[len(results)-1], sortBy, orderBy, false); err != nil {
+			return nil, meta, err
+		}
+	}
+	if hasPrev {
+		if meta.PrevCursor, err = r.encodeCursor(ctx, &results[0], sortBy, orderBy, true); err != nil {
+			return nil, meta, err
+		}
+	}
+	return results, meta, nil
+}
+
+func (r *BaseRepository[T]) encodeCursor(ctx context.Context, item *T, sortBy, orderBy string, backward bool) (string, error) {
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(item); err != nil {
+		return "", err
+	}
+	sortField := stmt.Schema.LookUpField(sortBy)
+	if sortField == nil {
+		return "", apperrors.Internal("imleç için sıralama alanı bulunamadı: "+sortBy, nil)
+	}
+
+	value, _ := sortField.ValueOf(ctx, reflect.ValueOf(item).Elem())
+	idValue, err := r.entityID(ctx, item)
+	if err != nil {
+		return "", err
+	}
+
+	return queryparams.EncodeCursor(queryparams.Cursor{
+		SortBy:   sortBy,
+		OrderBy:  orderBy,
+		Value:    value,
+		ID:       idValue,
+		Backward: backward,
+	})
+}
+
+func (r *BaseRepository[T]) entityID(ctx context.Context, item *T) (uint, error) {
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(item); err != nil {
+		return 0, err
+	}
+	idField := stmt.Schema.LookUpField("id")
+	if idField == nil {
+		return 0, apperrors.Internal("kayıt ID'si okunamadı", nil)
+	}
+	id, _ := idField.ValueOf(ctx, reflect.ValueOf(item).Elem())
+	idValue, ok := id.(uint)
+	if !ok {
+		return 0, apperrors.Internal("kayıt ID'si okunamadı", nil)
+	}
+	return idValue, nil
 }
 
-func (r *BaseRepository[T]) GetByID(id uint) (*T, error) {
+func (r *BaseRepository[T]) GetByID(ctx context.Context, id uint) (*T, error) {
 	var result T
-	err := r.db.First(&result, id).Error
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return nil, ErrNotFound
+	if err := r.readQuery(ctx).First(&result, id).Error; err != nil {
+		return nil, translateError(err)
 	}
-	return &result, err
+	return &result, nil
 }
 
 func (r *BaseRepository[T]) Create(ctx context.Context, entity *T) error {
-	return r.db.WithContext(ctx).Create(entity).Error
+	if err := r.stampTenant(ctx, entity); err != nil {
+		return err
+	}
+	return translateError(txmanager.DB(ctx, r.db).Create(entity).Error)
 }
 
 func (r *BaseRepository[T]) BulkCreate(ctx context.Context, entities []T) error {
-	return r.db.WithContext(ctx).Create(&entities).Error
+	for i := range entities {
+		if err := r.stampTenant(ctx, &entities[i]); err != nil {
+			return err
+		}
+	}
+	return translateError(txmanager.DB(ctx, r.db).Create(&entities).Error)
 }
 
-func (r *BaseRepository[T]) Update(ctx context.Context, id uint, data map[string]interface{}, updatedBy uint) error {
+// Update kaydı değişikliğe göre günceller ve yazılan sütunları günlüğe kaydeder.
+func (r *BaseRepository[T]) Update(ctx context.Context, id uint, patch Patch, updatedBy uint) error {
+	p, err := r.resolvePatch(ctx, patch)
+	if err != nil {
+		return err
+	}
+	data := p.data
 	if updatedBy > 0 {
 		data["updated_by"] = updatedBy
 	}
-	var t T
-	result := r.db.WithContext(ctx).Model(&t).Where("id = ?", id).Updates(data)
-	if result.RowsAffected == 0 {
+	expected, checkVersion := r.takeVersion(data)
+
+	query := r.query(ctx).Where("id = ?", id)
+	if checkVersion {
+		query = query.Where(models.VersionColumn+" = ?", expected)
+	}
+	result := query.Updates(data)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected > 0 {
+		logconfig.Log.Info("Kayıt güncellendi", zap.String("table", p.table), zap.Uint("id", id), zap.Strings("columns", p.columns))
+		return nil
+	}
+	if !checkVersion {
 		return ErrNotFound
 	}
-	return result.Error
+
+	var count int64
+	if err := r.query(ctx).Where("id = ?", id).Count(&count).Error; err != nil {
+		return translateError(err)
+	}
+	if count > 0 {
+		return ErrConflict
+	}
+	return ErrNotFound
 }
 
-func (r *BaseRepository[T]) BulkUpdate(ctx context.Context, condition map[string]interface{}, data map[string]interface{}, updatedBy uint) error {
+// BulkUpdate güncellenen kayıt sayısını döner. data içinde sürüm verilmişse koşula
+// uyan kayıtlardan biri bile farklı sürümdeyse hiçbirini güncellemez ve ErrConflict döner.
+func (r *BaseRepository[T]) BulkUpdate(ctx context.Context, condition map[string]interface{}, patch Patch, updatedBy uint) (int64, error) {
+	p, err := r.resolvePatch(ctx, patch)
+	if err != nil {
+		return 0, err
+	}
+	data := p.data
 	if updatedBy > 0 {
 		data["updated_by"] = updatedBy
 	}
-	var t T
-	return r.db.WithContext(ctx).Model(&t).Where(condition).Updates(data).Error
+	expected, checkVersion := r.takeVersion(data)
+	if !checkVersion {
+		result := r.query(ctx).Where(condition).Updates(data)
+		if result.Error != nil {
+			return 0, translateError(result.Error)
+		}
+		logBulkUpdate(p, result.RowsAffected)
+		return result.RowsAffected, nil
+	}
+
+	var updated int64
+	err = r.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var matched int64
+		if err := r.query(ctx).Where(condition).Count(&matched).Error; err != nil {
+			return translateError(err)
+		}
+		result := r.query(ctx).Where(condition).Where(models.VersionColumn+" = ?", expected).Updates(data)
+		if result.Error != nil {
+			return translateError(result.Error)
+		}
+		if result.RowsAffected < matched {
+			return ErrConflict
+		}
+		updated = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	logBulkUpdate(p, updated)
+	return updated, nil
+}
+
+func logBulkUpdate(p resolvedPatch, rows int64) {
+	if rows > 0 {
+		logconfig.Log.Info("Kayıtlar toplu güncellendi", zap.String("table", p.table), zap.Int64("rows", rows), zap.Strings("columns", p.columns))
+	}
+}
+
+// takeVersion sürümlü modellerde güncellemeye sürüm artışını ekler. data içinde
+// "version" anahtarıyla okunan sürüm verilmişse onu çıkarıp kontrol için döner.
+func (r *BaseRepository[T]) takeVersion(data map[string]interface{}) (uint, bool) {
+	if !r.versioned {
+		return 0, false
+	}
+	expected, ok := data[models.VersionColumn].(uint)
+	data[models.VersionColumn] = gorm.Expr(models.VersionColumn + " + 1")
+	return expected, ok && expected > 0
 }
 
 func (r *BaseRepository[T]) Delete(ctx context.Context, id uint) error {
@@ -140,51 +423,88 @@ func (r *BaseRepository[T]) Delete(ctx context.Context, id uint) error {
 		return ErrMissingUserID
 	}
 
-	tx := r.db.WithContext(ctx)
+	return r.tx.WithinTx(ctx, func(ctx context.Context) error {
+		tx := txmanager.DB(ctx, r.db)
 
-	if err := tx.First(&entity, id).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return ErrNotFound
+		if err := r.query(ctx).First(&entity, id).Error; err != nil {
+			return translateError(err)
 		}
-		return err
-	}
 
-	if err := tx.Model(&entity).Update("deleted_by", userID).Error; err != nil {
-		return err
-	}
+		if err := tx.Model(&entity).Update("deleted_by", userID).Error; err != nil {
+			return translateError(err)
+		}
 
-	return tx.Delete(&entity).Error
+		return translateError(tx.Delete(&entity).Error)
+	})
 }
 
-func (r *BaseRepository[T]) BulkDelete(ctx context.Context, condition map[string]interface{}) error {
-	var entities []T
-
+// BulkDelete koşula uyan kayıtları tek bir UPDATE ile yumuşak siler ve silinen kayıt
+// sayısını döner; kayıtlar belleğe okunmaz.
+func (r *BaseRepository[T]) BulkDelete(ctx context.Context, condition map[string]interface{}) (int64, error) {
 	userID, ok := ctx.Value(userIDKey).(uint)
 	if !ok || userID == 0 {
-		return ErrMissingUserID
+		return 0, ErrMissingUserID
 	}
 
-	tx := r.db.WithContext(ctx)
+	query := r.query(ctx)
+	result := query.Where(condition).Updates(map[string]interface{}{
+		"deleted_at": query.NowFunc(),
+		"deleted_by": userID,
+	})
+	return result.RowsAffected, translateError(result.Error)
+}
+
+// Restore yumuşak silinmiş bir kaydı geri getirir.
+func (r *BaseRepository[T]) Restore(ctx context.Context, id uint) error {
+	result := r.query(ctx).Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{"deleted_at": nil, "deleted_by": nil})
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *BaseRepository[T]) GetCount(ctx context.Context) (int64, error) {
+	var totalCount int64
+	err := r.readQuery(ctx).Count(&totalCount).Error
+	return totalCount, translateError(err)
+}
+
+func (r *BaseRepository[T]) GetFilteredCount(ctx context.Context, params queryparams.ListParams) (int64, error) {
+	var totalCount int64
+	query, err := r.applyFilters(r.readQuery(ctx), params)
+	if err != nil {
+		return 0, err
+	}
+	err = query.Count(&totalCount).Error
+	return totalCount, translateError(err)
+}
 
-	if err := tx.Where(condition).Find(&entities).Error; err != nil {
+func (r *BaseRepository[T]) Stream(ctx context.Context, params queryparams.ListParams, fn func(item *T) error) error {
+	query, err := r.applyFilters(r.readQuery(ctx), params)
+	if err != nil {
 		return err
 	}
+	query = r.applySort(query, params)
 
-	for _, entity := range entities {
-		if err := tx.Model(&entity).Update("deleted_by", userID).Error; err != nil {
-			return err
+	rows, err := query.Rows()
+	if err != nil {
+		return translateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item T
+		if err := query.ScanRows(rows, &item); err != nil {
+			return translateError(err)
 		}
-		if err := tx.Delete(&entity).Error; err != nil {
+		if err := fn(&item); err != nil {
 			return err
 		}
 	}
-
-	return nil
-}
-
-func (r *BaseRepository[T]) GetCount() (int64, error) {
-	var totalCount int64
-	var t T
-	err := r.db.Model(&t).Count(&totalCount).Error
-	return totalCount, err
+	return translateError(rows.Err())
 }
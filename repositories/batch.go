@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"context"
+
+	"zatrano/pkg/queryparams"
+
+	"gorm.io/gorm"
+)
+
+const DefaultBatchSize = 500
+
+// BatchOptions InBatches ve Each için ayarlardır.
+type BatchOptions struct {
+	// Size bir partide okunacak kayıt sayısıdır; 0 ise DefaultBatchSize kullanılır.
+	Size int
+	// Transactional her partiyi, okuması dahil kendi işleminde çalıştırır. fn hata
+	// dönerse yalnızca o partinin değişiklikleri geri alınır; önceki partiler kalıcıdır.
+	// İşlem çakışmayla biterse parti yeniden okunup fn yeniden çalıştırılır.
+	Transactional bool
+}
+
+// InBatches filtreye uyan tüm kayıtları ID sırasıyla, OFFSET yerine son ID'den devam
+// ederek (keyset) partiler halinde fn'e verir. Filtreler, arama, yumuşak silme ve
+// kiracı/sahiplik kapsamları uygulanır; params'taki sıralama ve sayfa dikkate alınmaz.
+// Her partiden önce context kontrol edilir; iptal edilirse context hatası döner.
+// fn'in partideki kayıtları silmesi ya da filtre dışına çıkarması sonraki partileri
+// etkilemez.
+func (r *BaseRepository[T]) InBatches(ctx context.Context, params queryparams.ListParams, opts BatchOptions, fn func(ctx context.Context, batch []T) error) error {
+	size := opts.Size
+	if size <= 0 {
+		size = DefaultBatchSize
+	}
+
+	var lastID uint
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var batch []T
+		run := func(ctx context.Context, query *gorm.DB) error {
+			query, err := r.applyFilters(query, params)
+			if err != nil {
+				return err
+			}
+			batch = batch[:0]
+			if err := query.Where("id > ?", lastID).Order("id").Limit(size).Find(&batch).Error; err != nil {
+				return translateError(err)
+			}
+			if len(batch) == 0 {
+				return nil
+			}
+			return fn(ctx, batch)
+		}
+
+		var err error
+		if opts.Transactional {
+			err = r.tx.WithinTx(ctx, func(ctx context.Context) error {
+				return run(ctx, r.query(ctx))
+			})
+		} else {
+			err = run(ctx, r.readQuery(ctx))
+		}
+		if err != nil {
+			return err
+		}
+		if len(batch) < size {
+			return nil
+		}
+
+		if lastID, err = r.entityID(ctx, &batch[len(batch)-1]); err != nil {
+			return err
+		}
+	}
+}
+
+// Each InBatches ile okunan kayıtları tek tek fn'e verir; kayıtlar arasında da
+// context kontrol edilir.
+func (r *BaseRepository[T]) Each(ctx context.Context, params queryparams.ListParams, opts BatchOptions, fn func(ctx context.Context, item *T) error) error {
+	return r.InBatches(ctx, params, opts, func(ctx context.Context, batch []T) error {
+		for i := range batch {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := fn(ctx, &batch[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
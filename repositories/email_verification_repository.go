@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"zatrano/configs/databaseconfig"
+	"zatrano/models"
+	"zatrano/pkg/txmanager"
+
+	"gorm.io/gorm"
+)
+
+type IEmailVerificationRepository interface {
+	CreateVerification(ctx context.Context, verification *models.EmailVerification) error
+	UpdateVerificationTokenHash(ctx context.Context, id uint, tokenHash string, updatedBy uint) error
+	FindVerificationByTokenHash(ctx context.Context, tokenHash string) (*models.EmailVerification, error)
+	ExpirePendingVerifications(ctx context.Context, userID uint) error
+	MarkVerificationConfirmed(ctx context.Context, id uint, confirmedBy uint) error
+}
+
+type EmailVerificationRepository struct {
+	db   *gorm.DB
+	base IBaseRepository[models.EmailVerification]
+}
+
+func NewEmailVerificationRepository() IEmailVerificationRepository {
+	db := databaseconfig.GetDB()
+	return &EmailVerificationRepository{db: db, base: NewBaseRepository[models.EmailVerification](db)}
+}
+
+func (r *EmailVerificationRepository) CreateVerification(ctx context.Context, verification *models.EmailVerification) error {
+	return r.base.Create(ctx, verification)
+}
+
+func (r *EmailVerificationRepository) UpdateVerificationTokenHash(ctx context.Context, id uint, tokenHash string, updatedBy uint) error {
+	return r.base.Update(ctx, id, PatchFields(&models.EmailVerification{TokenHash: tokenHash}, "token_hash"), updatedBy)
+}
+
+func (r *EmailVerificationRepository) FindVerificationByTokenHash(ctx context.Context, tokenHash string) (*models.EmailVerification, error) {
+	var verification models.EmailVerification
+	err := txmanager.DB(ctx, r.db).Where("token_hash = ?", tokenHash).First(&verification).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &verification, nil
+}
+
+// ExpirePendingVerifications kullanıcının bekleyen doğrulamalarını geçersiz kılar;
+// yalnızca en son talep edilen adres onaylanabilir.
+func (r *EmailVerificationRepository) ExpirePendingVerifications(ctx context.Context, userID uint) error {
+	err := txmanager.DB(ctx, r.db).Model(&models.EmailVerification{}).
+		Where("user_id = ? AND confirmed_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Update("expires_at", time.Now()).Error
+	return translateError(err)
+}
+
+func (r *EmailVerificationRepository) MarkVerificationConfirmed(ctx context.Context, id uint, confirmedBy uint) error {
+	result := txmanager.DB(ctx, r.db).Model(&models.EmailVerification{}).
+		Where("id = ? AND confirmed_at IS NULL AND expires_at > ?", id, time.Now()).
+		Updates(map[string]interface{}{
+			"confirmed_at": time.Now(),
+			"updated_by":   confirmedBy,
+		})
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+var _ IEmailVerificationRepository = (*EmailVerificationRepository)(nil)
@@ -0,0 +1,205 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"zatrano/configs/logconfig"
+	"zatrano/models"
+	"zatrano/pkg/apperrors"
+
+	"github.com/glebarez/go-sqlite"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// PostgreSQL SQLSTATE kodları: https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgUniqueViolation      = "23505"
+	pgForeignKeyViolation  = "23503"
+	pgNotNullViolation     = "23502"
+	pgCheckViolation       = "23514"
+	pgStringTooLong        = "22001"
+	pgInvalidTextValue     = "22P02"
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+	pgQueryCanceled        = "57014"
+)
+
+// MySQL hata numaraları: https://dev.mysql.com/doc/mysql-errors/8.0/en/server-error-reference.html
+const (
+	mysqlDuplicateEntry  = 1062
+	mysqlBadNull         = 1048
+	mysqlDataTooLong     = 1406
+	mysqlRowIsReferenced = 1451
+	mysqlNoReferencedRow = 1452
+	mysqlLockWaitTimeout = 1205
+	mysqlDeadlock        = 1213
+	mysqlQueryTimeout    = 3024
+)
+
+// SQLite genişletilmiş sonuç kodları: https://www.sqlite.org/rescode.html
+const (
+	sqliteConstraintUnique  = 2067
+	sqliteConstraintPrimary = 1555
+	sqliteConstraintNotNull = 1299
+	sqliteConstraintFK      = 787
+)
+
+var (
+	ErrNotFound      = apperrors.NotFound("kayıt bulunamadı")
+	ErrMissingUserID = apperrors.Unauthorized("context içinde geçerli user_id yok")
+	ErrConflict      = apperrors.Conflict("kayıt siz düzenlerken başka biri tarafından değiştirildi", models.VersionColumn)
+
+	// Kapsam bilgisi eksik sorgular bütün kayıtlara açılmak yerine bu hatalarla reddedilir.
+	ErrTenantRequired    = apperrors.Forbidden("kiracı bilgisi olmadan kiracıya bağlı kayıtlara erişilemez")
+	ErrOwnershipRequired = apperrors.Forbidden("sahiplik politikası olmadan kullanıcıya bağlı kayıtlara erişilemez")
+)
+
+var (
+	pgKeyColumnsPattern  = regexp.MustCompile(`Key \(([^)]+)\)`)
+	mysqlKeyPattern      = regexp.MustCompile(`for key '(?:(\w+)\.)?(\w+)'`)
+	mysqlColumnPattern   = regexp.MustCompile(`[Cc]olumn '(\w+)'`)
+	sqliteColumnsPattern = regexp.MustCompile(`constraint failed: (\w+\.\w+(?:, \w+\.\w+)*)`)
+)
+
+// translateError sürücü hatalarını apperrors türlerine çevirir. Zaten tipli olan
+// hatalar ve nil olduğu gibi döner.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := apperrors.As(err); ok {
+		return err
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		logconfig.Log.Warn("Veritabanı işlemi context süresi dolduğu için iptal edildi", zap.Error(err))
+		return &apperrors.Error{Kind: apperrors.KindTimeout, Message: "veritabanı işlemi zaman aşımına uğradı", Err: err}
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return translateMySQLError(err, mysqlErr)
+	}
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		return translateSQLiteError(err, sqliteErr)
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return apperrors.Conflict("kayıt zaten mevcut").WithCause(err)
+		}
+		return apperrors.Internal("veritabanı hatası", err)
+	}
+
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		fields := constraintFields(pgErr)
+		return apperrors.Conflict("bu değer zaten kullanılıyor", fields...).WithCause(err)
+	case pgForeignKeyViolation:
+		return apperrors.Conflict("kayıt ilişkili başka kayıtlar nedeniyle bu işlem yapılamaz", constraintFields(pgErr)...).WithCause(err)
+	case pgNotNullViolation:
+		return apperrors.Validation("zorunlu alan boş bırakılamaz", fieldName(pgErr.ColumnName)).WithCause(err)
+	case pgCheckViolation, pgStringTooLong, pgInvalidTextValue:
+		return apperrors.Validation("girilen değer geçersiz", constraintFields(pgErr)...).WithCause(err)
+	case pgSerializationFailure, pgDeadlockDetected:
+		return apperrors.Conflict("kayıt aynı anda başka bir işlem tarafından değiştirildi, lütfen tekrar deneyin").WithCause(err)
+	case pgQueryCanceled:
+		logconfig.Log.Warn("Veritabanı sorgusu statement_timeout nedeniyle iptal edildi", zap.Error(err))
+		return &apperrors.Error{Kind: apperrors.KindTimeout, Message: "veritabanı işlemi zaman aşımına uğradı", Err: err}
+	default:
+		return apperrors.Internal("veritabanı hatası", err)
+	}
+}
+
+func translateMySQLError(err error, mysqlErr *mysql.MySQLError) error {
+	switch mysqlErr.Number {
+	case mysqlDuplicateEntry:
+		var fields []string
+		if m := mysqlKeyPattern.FindStringSubmatch(mysqlErr.Message); len(m) == 3 {
+			// gorm indeks adları idx_<tablo>_<sütun> biçimindedir.
+			column := strings.TrimPrefix(m[2], "idx_"+m[1]+"_")
+			fields = append(fields, fieldName(strings.TrimPrefix(column, "uni_"+m[1]+"_")))
+		}
+		return apperrors.Conflict("bu değer zaten kullanılıyor", fields...).WithCause(err)
+	case mysqlRowIsReferenced, mysqlNoReferencedRow:
+		return apperrors.Conflict("kayıt ilişkili başka kayıtlar nedeniyle bu işlem yapılamaz").WithCause(err)
+	case mysqlBadNull:
+		return apperrors.Validation("zorunlu alan boş bırakılamaz", mysqlColumnFields(mysqlErr)...).WithCause(err)
+	case mysqlDataTooLong:
+		return apperrors.Validation("girilen değer geçersiz", mysqlColumnFields(mysqlErr)...).WithCause(err)
+	case mysqlDeadlock, mysqlLockWaitTimeout:
+		return apperrors.Conflict("kayıt aynı anda başka bir işlem tarafından değiştirildi, lütfen tekrar deneyin").WithCause(err)
+	case mysqlQueryTimeout:
+		logconfig.Log.Warn("Veritabanı sorgusu max_execution_time nedeniyle iptal edildi", zap.Error(err))
+		return &apperrors.Error{Kind: apperrors.KindTimeout, Message: "veritabanı işlemi zaman aşımına uğradı", Err: err}
+	default:
+		return apperrors.Internal("veritabanı hatası", err)
+	}
+}
+
+func mysqlColumnFields(mysqlErr *mysql.MySQLError) []string {
+	if m := mysqlColumnPattern.FindStringSubmatch(mysqlErr.Message); len(m) == 2 {
+		return []string{fieldName(m[1])}
+	}
+	return nil
+}
+
+func translateSQLiteError(err error, sqliteErr *sqlite.Error) error {
+	switch sqliteErr.Code() {
+	case sqliteConstraintUnique, sqliteConstraintPrimary:
+		return apperrors.Conflict("bu değer zaten kullanılıyor", sqliteColumnFields(sqliteErr)...).WithCause(err)
+	case sqliteConstraintFK:
+		return apperrors.Conflict("kayıt ilişkili başka kayıtlar nedeniyle bu işlem yapılamaz").WithCause(err)
+	case sqliteConstraintNotNull:
+		return apperrors.Validation("zorunlu alan boş bırakılamaz", sqliteColumnFields(sqliteErr)...).WithCause(err)
+	default:
+		return apperrors.Internal("veritabanı hatası", err)
+	}
+}
+
+// sqliteColumnFields "UNIQUE constraint failed: users.account_key" mesajındaki
+// sütunları form alan adlarına çevirir.
+func sqliteColumnFields(sqliteErr *sqlite.Error) []string {
+	m := sqliteColumnsPattern.FindStringSubmatch(sqliteErr.Error())
+	if len(m) != 2 {
+		return nil
+	}
+	var columns []string
+	for _, col := range strings.Split(m[1], ", ") {
+		if i := strings.LastIndex(col, "."); i >= 0 {
+			col = col[i+1:]
+		}
+		columns = append(columns, fieldName(col))
+	}
+	return columns
+}
+
+// constraintFields hatanın ilgili olduğu sütunları form alan adlarına çevirir.
+// Detail ("Key (account_key)=(ali) already exists.") yoksa sütun adına düşülür.
+func constraintFields(pgErr *pgconn.PgError) []string {
+	var columns []string
+	if m := pgKeyColumnsPattern.FindStringSubmatch(pgErr.Detail); len(m) == 2 {
+		for _, col := range strings.Split(m[1], ",") {
+			columns = append(columns, fieldName(strings.TrimSpace(col)))
+		}
+	} else if pgErr.ColumnName != "" {
+		columns = append(columns, fieldName(pgErr.ColumnName))
+	}
+	return columns
+}
+
+// fieldName karşılaştırma için türetilmiş sütunları formdaki asıl alana eşler
+// (ör. account_key → account).
+func fieldName(column string) string {
+	return strings.TrimSuffix(column, "_key")
+}
@@ -0,0 +1,243 @@
+package repositories
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"zatrano/pkg/apperrors"
+	"zatrano/pkg/queryparams"
+	"zatrano/pkg/turkishsearch"
+
+	"gorm.io/gorm"
+)
+
+type FilterType int
+
+const (
+	FilterString FilterType = iota
+	FilterNumber
+	FilterBool
+	FilterDate
+)
+
+// FilterField bir alanın hangi sütuna ve hangi operatörlerle filtrelenebileceğini
+// tanımlar. İlk operatör, alan yalın yazıldığında (name=ali) kullanılan varsayılandır.
+// Values doluysa eq/ne/in değerleri bu listeyle sınırlanır.
+type FilterField struct {
+	Column    string
+	Type      FilterType
+	Operators []queryparams.Operator
+	Values    []string
+}
+
+// FilterSchema sorgu dizesinde izin verilen filtre alanlarıdır; şemada olmayan alanlar
+// doğrulama hatasıyla reddedilir.
+type FilterSchema map[string]FilterField
+
+const filterDateLayout = "2006-01-02"
+
+var filterTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02T15:04"}
+
+func (s FilterSchema) apply(query *gorm.DB, filters []queryparams.Filter) (*gorm.DB, error) {
+	for _, f := range filters {
+		field, ok := s[f.Field]
+		if !ok {
+			return nil, apperrors.Validation("bilinmeyen filtre alanı: "+f.Field, f.Field)
+		}
+
+		op := f.Operator
+		if op == "" && len(field.Operators) > 0 {
+			op = field.Operators[0]
+		}
+		if !field.allows(op) {
+			return nil, apperrors.Validation("'"+f.Field+"' alanı '"+string(op)+"' filtresini desteklemiyor", f.Field)
+		}
+
+		column := field.Column
+		if column == "" {
+			column = f.Field
+		}
+		sql, args, err := field.clause(column, op, f.Values)
+		if err != nil {
+			return nil, apperrors.Validation("'"+f.Field+"' filtresi için geçersiz değer", f.Field).WithCause(err)
+		}
+		query = query.Where(sql, args...)
+	}
+	return query, nil
+}
+
+func (f FilterField) allows(op queryparams.Operator) bool {
+	for _, allowed := range f.Operators {
+		if allowed == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (f FilterField) clause(column string, op queryparams.Operator, values []string) (string, []interface{}, error) {
+	switch op {
+	case queryparams.OpNull:
+		isNull, err := strconv.ParseBool(values[0])
+		if err != nil {
+			return "", nil, err
+		}
+		if isNull {
+			return column + " IS NULL", nil, nil
+		}
+		return column + " IS NOT NULL", nil, nil
+
+	case queryparams.OpContains:
+		if f.Type != FilterString {
+			return "", nil, errFilterValue
+		}
+		sql, args := turkishsearch.SQLFilter(column, values[0])
+		return sql, args, nil
+
+	case queryparams.OpIn:
+		args := make([]interface{}, 0, len(values))
+		for _, raw := range values {
+			v, err := f.convert(raw)
+			if err != nil {
+				return "", nil, err
+			}
+			args = append(args, v)
+		}
+		if len(args) == 0 {
+			return "", nil, errFilterValue
+		}
+		return column + " IN ?", []interface{}{args}, nil
+
+	case queryparams.OpBetween:
+		if len(values) != 2 {
+			return "", nil, errFilterValue
+		}
+		if f.Type == FilterDate {
+			from, _, err := parseFilterTime(values[0])
+			if err != nil {
+				return "", nil, err
+			}
+			to, dateOnly, err := parseFilterTime(values[1])
+			if err != nil {
+				return "", nil, err
+			}
+			// Yalnızca tarih verilen üst sınır o günün tamamını kapsar.
+			if dateOnly {
+				return column + " >= ? AND " + column + " < ?", []interface{}{from, to.AddDate(0, 0, 1)}, nil
+			}
+			return column + " BETWEEN ? AND ?", []interface{}{from, to}, nil
+		}
+		from, err := f.convert(values[0])
+		if err != nil {
+			return "", nil, err
+		}
+		to, err := f.convert(values[1])
+		if err != nil {
+			return "", nil, err
+		}
+		return column + " BETWEEN ? AND ?", []interface{}{from, to}, nil
+	}
+
+	if f.Type == FilterDate {
+		return dateClause(column, op, values[0])
+	}
+
+	v, err := f.convert(values[0])
+	if err != nil {
+		return "", nil, err
+	}
+	switch op {
+	case queryparams.OpEq:
+		return column + " = ?", []interface{}{v}, nil
+	case queryparams.OpNe:
+		return column + " <> ?", []interface{}{v}, nil
+	case queryparams.OpGt:
+		return column + " > ?", []interface{}{v}, nil
+	case queryparams.OpGte:
+		return column + " >= ?", []interface{}{v}, nil
+	case queryparams.OpLt:
+		return column + " < ?", []interface{}{v}, nil
+	case queryparams.OpLte:
+		return column + " <= ?", []interface{}{v}, nil
+	}
+	return "", nil, errFilterValue
+}
+
+// dateClause yalnızca tarih verilen koşulları gün aralığı olarak yorumlar;
+// created_at[lte]=2025-01-31 o günün sonuna kadar olan kayıtları da içerir.
+func dateClause(column string, op queryparams.Operator, raw string) (string, []interface{}, error) {
+	t, dateOnly, err := parseFilterTime(raw)
+	if err != nil {
+		return "", nil, err
+	}
+	if !dateOnly {
+		switch op {
+		case queryparams.OpEq:
+			return column + " = ?", []interface{}{t}, nil
+		case queryparams.OpNe:
+			return column + " <> ?", []interface{}{t}, nil
+		case queryparams.OpGt:
+			return column + " > ?", []interface{}{t}, nil
+		case queryparams.OpGte:
+			return column + " >= ?", []interface{}{t}, nil
+		case queryparams.OpLt:
+			return column + " < ?", []interface{}{t}, nil
+		case queryparams.OpLte:
+			return column + " <= ?", []interface{}{t}, nil
+		}
+		return "", nil, errFilterValue
+	}
+
+	next := t.AddDate(0, 0, 1)
+	switch op {
+	case queryparams.OpEq:
+		return column + " >= ? AND " + column + " < ?", []interface{}{t, next}, nil
+	case queryparams.OpNe:
+		return "(" + column + " < ? OR " + column + " >= ?)", []interface{}{t, next}, nil
+	case queryparams.OpGt:
+		return column + " >= ?", []interface{}{next}, nil
+	case queryparams.OpGte:
+		return column + " >= ?", []interface{}{t}, nil
+	case queryparams.OpLt:
+		return column + " < ?", []interface{}{t}, nil
+	case queryparams.OpLte:
+		return column + " < ?", []interface{}{next}, nil
+	}
+	return "", nil, errFilterValue
+}
+
+var errFilterValue = apperrors.Validation("geçersiz filtre değeri")
+
+func (f FilterField) convert(raw string) (interface{}, error) {
+	switch f.Type {
+	case FilterNumber:
+		return strconv.ParseInt(raw, 10, 64)
+	case FilterBool:
+		return strconv.ParseBool(raw)
+	case FilterDate:
+		t, _, err := parseFilterTime(raw)
+		return t, err
+	}
+	if len(f.Values) > 0 {
+		for _, allowed := range f.Values {
+			if strings.EqualFold(allowed, raw) {
+				return allowed, nil
+			}
+		}
+		return nil, errFilterValue
+	}
+	return raw, nil
+}
+
+func parseFilterTime(raw string) (time.Time, bool, error) {
+	if t, err := time.ParseInLocation(filterDateLayout, raw, time.Local); err == nil {
+		return t, true, nil
+	}
+	for _, layout := range filterTimeLayouts {
+		if t, err := time.ParseInLocation(layout, raw, time.Local); err == nil {
+			return t, false, nil
+		}
+	}
+	return time.Time{}, false, errFilterValue
+}
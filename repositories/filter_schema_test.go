@@ -0,0 +1,230 @@
+package repositories
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"zatrano/pkg/apperrors"
+	"zatrano/pkg/queryparams"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type filterRow struct {
+	ID        uint
+	Name      string
+	Type      string
+	Status    bool
+	CreatedAt time.Time
+}
+
+var testFilterSchema = FilterSchema{
+	"id":         {Type: FilterNumber, Operators: []queryparams.Operator{queryparams.OpEq, queryparams.OpIn, queryparams.OpBetween}},
+	"name":       {Type: FilterString, Operators: []queryparams.Operator{queryparams.OpEq, queryparams.OpNe}},
+	"type":       {Type: FilterString, Operators: []queryparams.Operator{queryparams.OpEq, queryparams.OpIn}, Values: []string{"panel", "dashboard"}},
+	"status":     {Type: FilterBool, Operators: []queryparams.Operator{queryparams.OpEq}},
+	"created":    {Column: "created_at", Type: FilterDate, Operators: []queryparams.Operator{queryparams.OpEq, queryparams.OpGt, queryparams.OpLte, queryparams.OpBetween}},
+	"deleted_by": {Type: FilterNumber, Operators: []queryparams.Operator{queryparams.OpNull}},
+}
+
+// filterQuery filtreleri çalıştırmadan uygular ve üretilen SQL ile parametreleri döner.
+func filterQuery(t *testing.T, filters ...queryparams.Filter) (string, []interface{}, error) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{DryRun: true, Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	query, err := testFilterSchema.apply(db.Model(&filterRow{}), filters)
+	if err != nil {
+		return "", nil, err
+	}
+	stmt := query.Find(&[]filterRow{}).Statement
+	return stmt.SQL.String(), stmt.Vars, nil
+}
+
+func TestFilterSchemaApply(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter queryparams.Filter
+		sql    string
+		vars   []interface{}
+	}{
+		{
+			name:   "yalın alan varsayılan operatörü kullanır",
+			filter: queryparams.Filter{Field: "name", Values: []string{"ali"}},
+			sql:    "SELECT * FROM `filter_rows` WHERE name = ?",
+			vars:   []interface{}{"ali"},
+		},
+		{
+			name:   "ne",
+			filter: queryparams.Filter{Field: "name", Operator: queryparams.OpNe, Values: []string{"ali"}},
+			sql:    "SELECT * FROM `filter_rows` WHERE name <> ?",
+			vars:   []interface{}{"ali"},
+		},
+		{
+			name:   "sayı dönüştürülür",
+			filter: queryparams.Filter{Field: "id", Operator: queryparams.OpEq, Values: []string{"42"}},
+			sql:    "SELECT * FROM `filter_rows` WHERE id = ?",
+			vars:   []interface{}{int64(42)},
+		},
+		{
+			name:   "in",
+			filter: queryparams.Filter{Field: "id", Operator: queryparams.OpIn, Values: []string{"1", "2"}},
+			sql:    "SELECT * FROM `filter_rows` WHERE id IN (?,?)",
+			vars:   []interface{}{int64(1), int64(2)},
+		},
+		{
+			name:   "sayı aralığı",
+			filter: queryparams.Filter{Field: "id", Operator: queryparams.OpBetween, Values: []string{"1", "10"}},
+			sql:    "SELECT * FROM `filter_rows` WHERE id BETWEEN ? AND ?",
+			vars:   []interface{}{int64(1), int64(10)},
+		},
+		{
+			name:   "izinli değer listedeki yazımıyla kullanılır",
+			filter: queryparams.Filter{Field: "type", Values: []string{"PANEL"}},
+			sql:    "SELECT * FROM `filter_rows` WHERE type = ?",
+			vars:   []interface{}{"panel"},
+		},
+		{
+			name:   "bool",
+			filter: queryparams.Filter{Field: "status", Values: []string{"false"}},
+			sql:    "SELECT * FROM `filter_rows` WHERE status = ?",
+			vars:   []interface{}{false},
+		},
+		{
+			name:   "null",
+			filter: queryparams.Filter{Field: "deleted_by", Operator: queryparams.OpNull, Values: []string{"true"}},
+			sql:    "SELECT * FROM `filter_rows` WHERE deleted_by IS NULL",
+		},
+		{
+			name:   "not null",
+			filter: queryparams.Filter{Field: "deleted_by", Operator: queryparams.OpNull, Values: []string{"false"}},
+			sql:    "SELECT * FROM `filter_rows` WHERE deleted_by IS NOT NULL",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, vars, err := filterQuery(t, tt.filter)
+			if err != nil {
+				t.Fatalf("beklenmeyen hata: %v", err)
+			}
+			if sql != tt.sql {
+				t.Errorf("sql = %q, want %q", sql, tt.sql)
+			}
+			if len(vars) != 0 || len(tt.vars) != 0 {
+				if !reflect.DeepEqual(vars, tt.vars) {
+					t.Errorf("vars = %#v, want %#v", vars, tt.vars)
+				}
+			}
+		})
+	}
+}
+
+func TestFilterSchemaRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter queryparams.Filter
+	}{
+		{name: "şemada olmayan alan", filter: queryparams.Filter{Field: "password", Values: []string{"x"}}},
+		{name: "alanın desteklemediği operatör", filter: queryparams.Filter{Field: "name", Operator: queryparams.OpContains, Values: []string{"a"}}},
+		{name: "sayı olmayan değer", filter: queryparams.Filter{Field: "id", Operator: queryparams.OpEq, Values: []string{"abc"}}},
+		{name: "listede olmayan değer", filter: queryparams.Filter{Field: "type", Operator: queryparams.OpIn, Values: []string{"panel", "admin"}}},
+		{name: "geçersiz bool", filter: queryparams.Filter{Field: "status", Values: []string{"belki"}}},
+		{name: "geçersiz tarih", filter: queryparams.Filter{Field: "created", Values: []string{"2025-13-01"}}},
+		{name: "tek değerli aralık", filter: queryparams.Filter{Field: "id", Operator: queryparams.OpBetween, Values: []string{"1"}}},
+		{name: "geçersiz null değeri", filter: queryparams.Filter{Field: "deleted_by", Operator: queryparams.OpNull, Values: []string{"evet"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := filterQuery(t, tt.filter)
+			if !apperrors.IsKind(err, apperrors.KindValidation) {
+				t.Fatalf("hata = %v, doğrulama hatası bekleniyordu", err)
+			}
+			if _, ok := apperrors.Fields(err)[tt.filter.Field]; !ok {
+				t.Errorf("hata %q alanına bağlanmadı: %v", tt.filter.Field, apperrors.Fields(err))
+			}
+		})
+	}
+}
+
+func TestFilterDateBoundaries(t *testing.T) {
+	day := time.Date(2025, 1, 31, 0, 0, 0, 0, time.Local)
+	next := day.AddDate(0, 0, 1)
+	exact := time.Date(2025, 1, 31, 14, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		name   string
+		filter queryparams.Filter
+		where  string
+		vars   []interface{}
+	}{
+		{
+			name:   "yalnızca tarihle eşitlik günün tamamıdır",
+			filter: queryparams.Filter{Field: "created", Operator: queryparams.OpEq, Values: []string{"2025-01-31"}},
+			where:  "created_at >= ? AND created_at < ?",
+			vars:   []interface{}{day, next},
+		},
+		{
+			name:   "lte günün sonunu kapsar",
+			filter: queryparams.Filter{Field: "created", Operator: queryparams.OpLte, Values: []string{"2025-01-31"}},
+			where:  "created_at < ?",
+			vars:   []interface{}{next},
+		},
+		{
+			name:   "gt ertesi günden başlar",
+			filter: queryparams.Filter{Field: "created", Operator: queryparams.OpGt, Values: []string{"2025-01-31"}},
+			where:  "created_at >= ?",
+			vars:   []interface{}{next},
+		},
+		{
+			name:   "saat verilirse tam an karşılaştırılır",
+			filter: queryparams.Filter{Field: "created", Operator: queryparams.OpEq, Values: []string{"2025-01-31T14:30"}},
+			where:  "created_at = ?",
+			vars:   []interface{}{exact},
+		},
+		{
+			name:   "tarih aralığının üst sınırı günün tamamıdır",
+			filter: queryparams.Filter{Field: "created", Operator: queryparams.OpBetween, Values: []string{"2025-01-01", "2025-01-31"}},
+			where:  "created_at >= ? AND created_at < ?",
+			vars:   []interface{}{time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local), next},
+		},
+		{
+			name:   "saatli aralık olduğu gibi kullanılır",
+			filter: queryparams.Filter{Field: "created", Operator: queryparams.OpBetween, Values: []string{"2025-01-01", "2025-01-31T14:30"}},
+			where:  "created_at BETWEEN ? AND ?",
+			vars:   []interface{}{time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local), exact},
+		},
+		{
+			name:   "ay sonundan sonraki gün bir sonraki aydır",
+			filter: queryparams.Filter{Field: "created", Operator: queryparams.OpLte, Values: []string{"2024-02-29"}},
+			where:  "created_at < ?",
+			vars:   []interface{}{time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, vars, err := filterQuery(t, tt.filter)
+			if err != nil {
+				t.Fatalf("beklenmeyen hata: %v", err)
+			}
+			if want := "SELECT * FROM `filter_rows` WHERE " + tt.where; sql != want {
+				t.Errorf("sql = %q, want %q", sql, want)
+			}
+			if len(vars) != len(tt.vars) {
+				t.Fatalf("vars = %v, want %v", vars, tt.vars)
+			}
+			for i := range vars {
+				got, ok := vars[i].(time.Time)
+				if !ok || !got.Equal(tt.vars[i].(time.Time)) {
+					t.Errorf("vars[%d] = %v, want %v", i, vars[i], tt.vars[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"context"
+
+	"zatrano/configs/databaseconfig"
+	"zatrano/models"
+	"zatrano/pkg/txmanager"
+
+	"gorm.io/gorm"
+)
+
+type IHistoryRepository interface {
+	GetEntityHistory(ctx context.Context, model interface{}, entityID uint, limit int) ([]models.EntityHistory, error)
+}
+
+type HistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewHistoryRepository() IHistoryRepository {
+	return &HistoryRepository{db: databaseconfig.GetDB()}
+}
+
+// GetEntityHistory verilen modelin bir kaydına ait geçmişi en yeniden eskiye döner;
+// işlemi yapan kullanıcının adı ActorName alanına doldurulur.
+func (r *HistoryRepository) GetEntityHistory(ctx context.Context, model interface{}, entityID uint, limit int) ([]models.EntityHistory, error) {
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, translateError(err)
+	}
+
+	var entries []models.EntityHistory
+	err := txmanager.DB(ctx, r.db).
+		Model(&models.EntityHistory{}).
+		Select("entity_histories.*, users.name AS actor_name").
+		Joins("LEFT JOIN users ON users.id = entity_histories.actor_id").
+		Where("entity_histories.entity_type = ? AND entity_histories.entity_id = ?", stmt.Schema.Table, entityID).
+		Order("entity_histories.created_at DESC, entity_histories.id DESC").
+		Limit(limit).
+		Find(&entries).Error
+	return entries, translateError(err)
+}
+
+var _ IHistoryRepository = (*HistoryRepository)(nil)
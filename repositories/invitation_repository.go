@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"zatrano/configs/databaseconfig"
+	"zatrano/models"
+	"zatrano/pkg/txmanager"
+
+	"gorm.io/gorm"
+)
+
+type IInvitationRepository interface {
+	CreateInvitation(ctx context.Context, invitation *models.UserInvitation) error
+	GetInvitationByID(ctx context.Context, id uint) (*models.UserInvitation, error)
+	FindInvitationByTokenHash(ctx context.Context, tokenHash string) (*models.UserInvitation, error)
+	GetLatestInvitations(ctx context.Context, userIDs []uint) (map[uint]*models.UserInvitation, error)
+	UpdateInvitation(ctx context.Context, id uint, patch Patch, updatedBy uint) error
+	MarkInvitationAccepted(ctx context.Context, id uint, acceptedBy uint) error
+}
+
+type InvitationRepository struct {
+	db   *gorm.DB
+	base IBaseRepository[models.UserInvitation]
+}
+
+func NewInvitationRepository() IInvitationRepository {
+	db := databaseconfig.GetDB()
+	return &InvitationRepository{db: db, base: NewBaseRepository[models.UserInvitation](db)}
+}
+
+func (r *InvitationRepository) CreateInvitation(ctx context.Context, invitation *models.UserInvitation) error {
+	return r.base.Create(ctx, invitation)
+}
+
+func (r *InvitationRepository) GetInvitationByID(ctx context.Context, id uint) (*models.UserInvitation, error) {
+	return r.base.GetByID(ctx, id)
+}
+
+func (r *InvitationRepository) FindInvitationByTokenHash(ctx context.Context, tokenHash string) (*models.UserInvitation, error) {
+	var invitation models.UserInvitation
+	err := txmanager.DB(ctx, r.db).Where("token_hash = ?", tokenHash).First(&invitation).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &invitation, nil
+}
+
+func (r *InvitationRepository) GetLatestInvitations(ctx context.Context, userIDs []uint) (map[uint]*models.UserInvitation, error) {
+	result := make(map[uint]*models.UserInvitation, len(userIDs))
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	var invitations []models.UserInvitation
+	err := txmanager.DB(ctx, r.db).
+		Where("user_id IN ?", userIDs).
+		Order("id desc").
+		Find(&invitations).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	for i := range invitations {
+		if _, exists := result[invitations[i].UserID]; !exists {
+			result[invitations[i].UserID] = &invitations[i]
+		}
+	}
+	return result, nil
+}
+
+func (r *InvitationRepository) UpdateInvitation(ctx context.Context, id uint, patch Patch, updatedBy uint) error {
+	return r.base.Update(ctx, id, patch, updatedBy)
+}
+
+// MarkInvitationAccepted daveti yalnızca hâlâ açıksa kabul edilmiş olarak işaretler;
+// aynı bağlantının eşzamanlı iki kez kullanılmasını engeller.
+func (r *InvitationRepository) MarkInvitationAccepted(ctx context.Context, id uint, acceptedBy uint) error {
+	result := txmanager.DB(ctx, r.db).Model(&models.UserInvitation{}).
+		Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"accepted_at": time.Now(),
+			"updated_by":  acceptedBy,
+		})
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+var _ IInvitationRepository = (*InvitationRepository)(nil)
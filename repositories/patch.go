@@ -0,0 +1,172 @@
+package repositories
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	"zatrano/configs/logconfig"
+	"zatrano/models"
+	"zatrano/pkg/apperrors"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// protectedColumns hiçbir güncellemede yazılamaz; kimlik ve iz sütunlarını depo
+// kendisi yönetir.
+var protectedColumns = map[string]bool{
+	"id":                true,
+	"created_at":        true,
+	"created_by":        true,
+	"updated_at":        true,
+	"updated_by":        true,
+	"deleted_at":        true,
+	"deleted_by":        true,
+	models.TenantColumn: true,
+}
+
+// Patch Update ve BulkUpdate'e verilen değişikliktir; PatchOf ya da PatchFields ile
+// oluşturulur. Sütunlar güncellenirken modelin UpdatableColumns listesine göre
+// denetlenir; bilinmeyen, korunan ya da listede olmayan sütunlar hata döner.
+type Patch struct {
+	values  map[string]interface{}
+	entity  interface{}
+	mask    []string
+	allowed []string
+	err     error
+}
+
+// PatchOf isteğe bağlı alanları olan bir yapıdan değişiklik oluşturur (ör.
+// models.UserPatch). Alan adları modeldeki alanlarla eşleşmelidir; nil işaretçi
+// alanlar atlanır, işaretçi olmayan alanlar sıfır değerleriyle de yazılır.
+func PatchOf(v interface{}) Patch {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return Patch{err: apperrors.Internal("güncelleme bir yapıdan oluşturulmalıdır", nil)}
+	}
+
+	values := make(map[string]interface{})
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		value := rv.Field(i)
+		if value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				continue
+			}
+			value = value.Elem()
+		}
+		values[field.Name] = value.Interface()
+	}
+	return Patch{values: values}
+}
+
+// PatchFields entity'nin yalnızca columns ile seçilen alanlarını yazar (alan maskesi);
+// seçilen alanlar sıfır değerliyse de yazılır. entity güncellenen modelin işaretçisi
+// olmalıdır.
+func PatchFields(entity interface{}, columns ...string) Patch {
+	return Patch{entity: entity, mask: columns}
+}
+
+// allow depodaki özel işlemlerin modelin listesinde olmayan sütunları (ör. şifre)
+// yazmasına izin verir; korunan sütunlar yine reddedilir.
+func (p Patch) allow(columns ...string) Patch {
+	p.allowed = append(append([]string(nil), p.allowed...), columns...)
+	return p
+}
+
+// resolvedPatch denetlenmiş değişikliktir; columns sürüm dışındaki yazılacak
+// sütunlardır ve yalnızca günlüğe yazılır.
+type resolvedPatch struct {
+	table   string
+	data    map[string]interface{}
+	columns []string
+}
+
+func (r *BaseRepository[T]) modelSchema() (*schema.Schema, error) {
+	var t T
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(&t); err != nil {
+		return nil, err
+	}
+	return stmt.Schema, nil
+}
+
+// resolvePatch değişikliği sütun adlarına çevirip modelin izin listesine göre denetler.
+// Sürümlü modellerde version okunan sürüm olarak kabul edilir, sütun olarak yazılmaz.
+func (r *BaseRepository[T]) resolvePatch(ctx context.Context, patch Patch) (resolvedPatch, error) {
+	if patch.err != nil {
+		return resolvedPatch{}, patch.err
+	}
+	s, err := r.modelSchema()
+	if err != nil {
+		return resolvedPatch{}, err
+	}
+
+	values := patch.values
+	if patch.entity != nil {
+		entity, ok := patch.entity.(*T)
+		if !ok {
+			return resolvedPatch{}, apperrors.Internal("alan maskesindeki kayıt güncellenen modelle aynı türde değil", nil)
+		}
+		rv := reflect.ValueOf(entity).Elem()
+		values = make(map[string]interface{}, len(patch.mask))
+		for _, name := range patch.mask {
+			field := s.LookUpField(name)
+			if field == nil {
+				return resolvedPatch{}, rejectColumn(s.Table, name, "bilinmeyen alan güncellenemez")
+			}
+			values[name], _ = field.ValueOf(ctx, rv)
+		}
+	}
+
+	allowed := make(map[string]bool)
+	var t T
+	if updatable, ok := any(&t).(models.Updatable); ok {
+		for _, column := range updatable.UpdatableColumns() {
+			allowed[column] = true
+		}
+	}
+	for _, column := range patch.allowed {
+		allowed[column] = true
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	p := resolvedPatch{table: s.Table, data: make(map[string]interface{}, len(values))}
+	for _, name := range names {
+		field := s.LookUpField(name)
+		switch {
+		case field == nil || field.DBName == "":
+			return resolvedPatch{}, rejectColumn(s.Table, name, "bilinmeyen alan güncellenemez")
+		case r.versioned && field.DBName == models.VersionColumn:
+			p.data[field.DBName] = values[name]
+			continue
+		case field.PrimaryKey || protectedColumns[field.DBName]:
+			return resolvedPatch{}, rejectColumn(s.Table, field.DBName, "korunan alan güncellenemez")
+		case !allowed[field.DBName] || !field.Updatable:
+			return resolvedPatch{}, rejectColumn(s.Table, field.DBName, "bu alanın güncellenmesine izin verilmiyor")
+		}
+		p.data[field.DBName] = values[name]
+		p.columns = append(p.columns, field.DBName)
+	}
+	if len(p.columns) == 0 {
+		return resolvedPatch{}, apperrors.Validation("güncellenecek alan belirtilmedi")
+	}
+	sort.Strings(p.columns)
+	return p, nil
+}
+
+func rejectColumn(table, column, message string) error {
+	logconfig.Log.Warn("Güncelleme reddedildi", zap.String("table", table), zap.String("column", column), zap.String("reason", message))
+	return apperrors.Validation(message+": "+column, fieldName(column))
+}
@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"zatrano/configs/logconfig"
+	"zatrano/models"
+	"zatrano/pkg/apperrors"
+
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// userPatchRepository şemayı okumak için sorgu çalıştırmayan bir kullanıcı deposu kurar.
+func userPatchRepository(t *testing.T) *BaseRepository[models.User] {
+	t.Helper()
+	if logconfig.Log == nil {
+		logconfig.Log = zap.NewNop()
+	}
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{DryRun: true, Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewBaseRepository[models.User](db)
+}
+
+func TestResolvePatch(t *testing.T) {
+	name := "Ayşe"
+	version := uint(3)
+
+	tests := []struct {
+		name    string
+		patch   Patch
+		data    map[string]interface{}
+		columns []string
+	}{
+		{
+			name:    "alan maskesi sıfır değerleri de yazar",
+			patch:   PatchFields(&models.User{Name: "Ayşe"}, "name", "status"),
+			data:    map[string]interface{}{"name": "Ayşe", "status": false},
+			columns: []string{"name", "status"},
+		},
+		{
+			name:    "nil alanlar atlanır",
+			patch:   PatchOf(models.UserPatch{Name: &name}),
+			data:    map[string]interface{}{"name": "Ayşe"},
+			columns: []string{"name"},
+		},
+		{
+			name:    "sürüm sütun olarak sayılmaz",
+			patch:   PatchOf(models.UserPatch{Name: &name, Version: &version}),
+			data:    map[string]interface{}{"name": "Ayşe", models.VersionColumn: uint(3)},
+			columns: []string{"name"},
+		},
+		{
+			name:    "depo izin verdiğinde listede olmayan sütun yazılır",
+			patch:   PatchFields(&models.User{Password: "hash"}, "password").allow("password"),
+			data:    map[string]interface{}{"password": "hash"},
+			columns: []string{"password"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := userPatchRepository(t).resolvePatch(context.Background(), tt.patch)
+			if err != nil {
+				t.Fatalf("beklenmeyen hata: %v", err)
+			}
+			if !reflect.DeepEqual(p.data, tt.data) {
+				t.Errorf("data = %#v, want %#v", p.data, tt.data)
+			}
+			if !reflect.DeepEqual(p.columns, tt.columns) {
+				t.Errorf("columns = %v, want %v", p.columns, tt.columns)
+			}
+		})
+	}
+}
+
+func TestResolvePatchRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		patch  Patch
+		column string
+	}{
+		{name: "modelde olmayan alan", patch: PatchFields(&models.User{}, "phone"), column: "phone"},
+		{name: "modelde olmayan yapı alanı", patch: PatchOf(struct{ Phone string }{Phone: "555"}), column: "Phone"},
+		{name: "birincil anahtar", patch: PatchFields(&models.User{}, "id"), column: "id"},
+		{name: "oluşturan kullanıcı", patch: PatchFields(&models.User{}, "created_by"), column: "created_by"},
+		{name: "kiracı", patch: PatchFields(&models.User{}, models.TenantColumn), column: models.TenantColumn},
+		{name: "izin verilse de korunan alan", patch: PatchFields(&models.User{}, "created_by").allow("created_by"), column: "created_by"},
+		{name: "izin listesinde olmayan alan", patch: PatchFields(&models.User{Password: "hash"}, "password"), column: "password"},
+		{name: "izin listesinde olmayan ilişki alanı", patch: PatchFields(&models.User{}, "parent_id"), column: "parent_id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := userPatchRepository(t).resolvePatch(context.Background(), tt.patch)
+			if !apperrors.IsKind(err, apperrors.KindValidation) {
+				t.Fatalf("hata = %v, doğrulama hatası bekleniyordu", err)
+			}
+			if _, ok := apperrors.Fields(err)[fieldName(tt.column)]; !ok {
+				t.Errorf("hata %q alanına bağlanmadı: %v", tt.column, apperrors.Fields(err))
+			}
+		})
+	}
+}
+
+func TestResolvePatchEmpty(t *testing.T) {
+	_, err := userPatchRepository(t).resolvePatch(context.Background(), PatchOf(models.UserPatch{}))
+	if !apperrors.IsKind(err, apperrors.KindValidation) {
+		t.Fatalf("hata = %v, boş güncelleme için doğrulama hatası bekleniyordu", err)
+	}
+}
+
+func TestPatchTypeMismatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch Patch
+	}{
+		{name: "başka modelin kaydı", patch: PatchFields(&models.Tenant{Name: "x"}, "name")},
+		{name: "işaretçi olmayan kayıt", patch: PatchFields(models.User{Name: "x"}, "name")},
+		{name: "yapı olmayan değişiklik", patch: PatchOf(map[string]interface{}{"name": "x"})},
+		{name: "nil değişiklik", patch: PatchOf(nil)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := userPatchRepository(t).resolvePatch(context.Background(), tt.patch)
+			if !apperrors.IsKind(err, apperrors.KindInternal) {
+				t.Fatalf("hata = %v, iç hata bekleniyordu", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"context"
+
+	"zatrano/models"
+	"zatrano/pkg/ownership"
+	"zatrano/pkg/tenancy"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *BaseRepository[T]) scopes(ctx context.Context) []func(*gorm.DB) *gorm.DB {
+	var scopes []func(*gorm.DB) *gorm.DB
+	if r.tenantScoped {
+		scopes = append(scopes, tenantScope(ctx))
+	}
+	if r.ownerFilter != nil {
+		scopes = append(scopes, ownerScope(ctx, r.ownerFilter))
+	}
+	return scopes
+}
+
+// scopeConditions kapsamların koşullarını sorgu dışında (ör. upsert çakışma koşulu)
+// kullanmak için döner.
+func (r *BaseRepository[T]) scopeConditions(ctx context.Context) ([]clause.Expression, error) {
+	var conditions []clause.Expression
+	if r.tenantScoped {
+		condition, err := tenantCondition(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if condition != nil {
+			conditions = append(conditions, condition)
+		}
+	}
+	if r.ownerFilter != nil {
+		condition, err := ownerCondition(ctx, r.ownerFilter)
+		if err != nil {
+			return nil, err
+		}
+		if condition != nil {
+			conditions = append(conditions, condition)
+		}
+	}
+	return conditions, nil
+}
+
+func tenantScope(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		condition, err := tenantCondition(ctx)
+		return whereCondition(db, condition, err)
+	}
+}
+
+// tenantCondition kiracı filtresini döner; atlama açıksa filtre yoktur. Context'te
+// ne kiracı ne de atlama varsa sorgu bütün kiracılara açılmasın diye hata döner.
+func tenantCondition(ctx context.Context) (clause.Expression, error) {
+	if tenancy.IsBypassed(ctx) {
+		return nil, nil
+	}
+	tenantID, ok := tenancy.TenantID(ctx)
+	if !ok {
+		return nil, ErrTenantRequired
+	}
+	return clause.Eq{
+		Column: clause.Column{Table: clause.CurrentTable, Name: models.TenantColumn},
+		Value:  tenantID,
+	}, nil
+}
+
+// ownerScope başka kullanıcılara ait kayıtları sorgudan çıkarır; erişim reddi
+// böylece ErrNotFound olarak döner ve kaydın varlığı gizli kalır.
+func ownerScope(ctx context.Context, filter func(ownerIDs []uint) clause.Expression) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		condition, err := ownerCondition(ctx, filter)
+		return whereCondition(db, condition, err)
+	}
+}
+
+// ownerCondition sahiplik filtresini döner; politika Bypass ise filtre yoktur.
+// Politika hiç yoksa kiracı filtresinde olduğu gibi hata döner.
+func ownerCondition(ctx context.Context, filter func(ownerIDs []uint) clause.Expression) (clause.Expression, error) {
+	policy, ok := ownership.PolicyFromContext(ctx)
+	if !ok {
+		return nil, ErrOwnershipRequired
+	}
+	if policy.Bypass {
+		return nil, nil
+	}
+	return filter(policy.OwnerIDs()), nil
+}
+
+// ownerColumnFilter kaydın sahibini tek bir sütunda tutan modellerin koşuludur.
+func ownerColumnFilter(column string) func(ownerIDs []uint) clause.Expression {
+	return func(ownerIDs []uint) clause.Expression {
+		return clause.IN{
+			Column: clause.Column{Table: clause.CurrentTable, Name: column},
+			Values: uintsToValues(ownerIDs),
+		}
+	}
+}
+
+// whereCondition koşulu sorguya ekler; hata varsa sorgu çalıştırılmadan hatayla döner.
+func whereCondition(db *gorm.DB, condition clause.Expression, err error) *gorm.DB {
+	if err != nil {
+		db.AddError(err)
+		return db
+	}
+	if condition != nil {
+		return db.Where(condition)
+	}
+	return db
+}
+
+func uintsToValues(ids []uint) []interface{} {
+	values := make([]interface{}, len(ids))
+	for i, id := range ids {
+		values[i] = id
+	}
+	return values
+}
+
+// stampTenant yeni kaydı context'teki kiracıya bağlar. Atlama açıkken kiracı
+// verilmemişse kaydın kendi kiracısı kullanılır; ikisi de yoksa kayıt reddedilir.
+func (r *BaseRepository[T]) stampTenant(ctx context.Context, entity *T) error {
+	if !r.tenantScoped {
+		return nil
+	}
+	tenantID, ok := tenancy.TenantID(ctx)
+	if !ok {
+		if tenancy.IsBypassed(ctx) {
+			return nil
+		}
+		return ErrTenantRequired
+	}
+	if scoped, ok := any(entity).(models.TenantScoped); ok {
+		scoped.SetTenantID(tenantID)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"zatrano/pkg/fulltext"
+	"zatrano/pkg/turkishsearch"
+
+	"gorm.io/gorm"
+)
+
+func (r *BaseRepository[T]) fullTextEnabled() bool {
+	return len(r.searchFields) > 0 && r.db.Dialector.Name() == "postgres"
+}
+
+// applySearch arama metnini modelin search_vector sütununda eşler. PostgreSQL dışındaki
+// sürücülerde arama alanları üzerinde Türkçe katlamalı LIKE ile yapılır.
+func (r *BaseRepository[T]) applySearch(query *gorm.DB, search string) *gorm.DB {
+	search = strings.TrimSpace(search)
+	if search == "" || len(r.searchFields) == 0 {
+		return query
+	}
+
+	if r.fullTextEnabled() {
+		return query.Where(fulltext.VectorColumn+" @@ "+fulltext.QueryExpression(), search)
+	}
+
+	conditions := make([]string, 0, len(r.searchFields))
+	var args []interface{}
+	for _, f := range r.searchFields {
+		condition, conditionArgs := turkishsearch.SQLFilter(f.Column, search)
+		conditions = append(conditions, condition)
+		args = append(args, conditionArgs...)
+	}
+	return query.Where("("+strings.Join(conditions, " OR ")+")", args...)
+}
+
+// applyRanking sonuçları önce arama sırasına (ts_rank_cd) dizer ve her kayda vurgulu
+// özeti search_headline olarak ekler. COUNT sorgusundan sonra uygulanmalıdır.
+func (r *BaseRepository[T]) applyRanking(query *gorm.DB, search string) *gorm.DB {
+	search = strings.TrimSpace(search)
+	if search == "" || !r.fullTextEnabled() {
+		return query
+	}
+
+	tsQuery := fulltext.QueryExpression()
+	query = query.Select(
+		"*, ts_rank_cd("+fulltext.VectorColumn+", "+tsQuery+") AS search_rank, "+
+			"ts_headline('"+fulltext.Config+"'::regconfig, "+fulltext.DocumentExpression(r.searchFields)+", "+tsQuery+", ?) AS search_headline",
+		search, search, fulltext.HeadlineOptions(),
+	)
+	return query.Order("search_rank DESC")
+}
+
+// Suggest arama sonuç vermediğinde gösterilecek "bunu mu demek istediniz" önerilerini
+// arama alanlarındaki değerlerden pg_trgm benzerliğine göre seçer. pg_trgm yoksa boş döner.
+func (r *BaseRepository[T]) Suggest(ctx context.Context, search string, limit int) ([]string, error) {
+	folded := turkishsearch.Fold(strings.TrimSpace(search))
+	if folded == "" || limit <= 0 || len(r.searchFields) == 0 || !turkishsearch.TrigramEnabled() {
+		return nil, nil
+	}
+
+	type suggestion struct {
+		Suggestion string
+		Score      float64
+	}
+	var candidates []suggestion
+	for _, f := range r.searchFields {
+		similarity := turkishsearch.SimilarityExpression(f.Column)
+		var rows []suggestion
+		err := r.readQuery(ctx).
+			Select(f.Column+" AS suggestion, "+similarity+" AS score", folded).
+			Where(f.Column+" IS NOT NULL").
+			Where(similarity+" >= ?", folded, turkishsearch.SuggestThreshold()).
+			Order("score DESC").
+			Limit(limit).
+			Scan(&rows).Error
+		if err != nil {
+			return nil, translateError(err)
+		}
+		candidates = append(candidates, rows...)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Score > candidates[j].Score })
+	seen := make(map[string]bool, len(candidates))
+	suggestions := make([]string, 0, limit)
+	for _, c := range candidates {
+		key := turkishsearch.Fold(c.Suggestion)
+		if seen[key] || key == folded {
+			continue
+		}
+		seen[key] = true
+		suggestions = append(suggestions, c.Suggestion)
+		if len(suggestions) == limit {
+			break
+		}
+	}
+	return suggestions, nil
+}
@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"context"
+
+	"zatrano/configs/databaseconfig"
+	"zatrano/models"
+	"zatrano/pkg/txmanager"
+
+	"gorm.io/gorm"
+)
+
+type ITenantRepository interface {
+	GetAllTenants(ctx context.Context) ([]models.Tenant, error)
+	GetTenantByID(ctx context.Context, id uint) (*models.Tenant, error)
+	FindTenantBySlug(ctx context.Context, slug string) (*models.Tenant, error)
+}
+
+type TenantRepository struct {
+	db   *gorm.DB
+	base IBaseRepository[models.Tenant]
+}
+
+func NewTenantRepository() ITenantRepository {
+	db := databaseconfig.GetDB()
+	return &TenantRepository{db: db, base: NewBaseRepository[models.Tenant](db)}
+}
+
+func (r *TenantRepository) GetAllTenants(ctx context.Context) ([]models.Tenant, error) {
+	var tenants []models.Tenant
+	err := txmanager.DB(ctx, r.db).Order("name asc").Find(&tenants).Error
+	return tenants, translateError(err)
+}
+
+func (r *TenantRepository) GetTenantByID(ctx context.Context, id uint) (*models.Tenant, error) {
+	return r.base.GetByID(ctx, id)
+}
+
+func (r *TenantRepository) FindTenantBySlug(ctx context.Context, slug string) (*models.Tenant, error) {
+	var tenant models.Tenant
+	err := txmanager.DB(ctx, r.db).Where("slug = ?", slug).First(&tenant).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &tenant, nil
+}
+
+var _ ITenantRepository = (*TenantRepository)(nil)
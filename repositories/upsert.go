@@ -0,0 +1,150 @@
+package repositories
+
+import (
+	"context"
+
+	"zatrano/models"
+	"zatrano/pkg/apperrors"
+	"zatrano/pkg/txmanager"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// UpsertOptions kayıt zaten varsa ne yapılacağını belirler.
+type UpsertOptions struct {
+	// Conflict çakışma hedefi olan benzersiz indeksin sütunlarıdır; boşsa birincil
+	// anahtar kullanılır. MySQL hedef belirtmeyi desteklemez, herhangi bir benzersiz
+	// indeksteki çakışmada güncelleme yapar.
+	Conflict []string
+	// Update çakışmada yeni değerleri yazılacak sütunlardır; boşsa oluşturma, silme,
+	// kiracı ve çakışma sütunları dışındaki tüm sütunlar güncellenir.
+	Update []string
+	// DoNothing çakışan kaydı olduğu gibi bırakır.
+	DoNothing bool
+}
+
+// upsertProtectedColumns varsayılan güncelleme listesine alınmaz: kaydı kimin
+// oluşturduğu değişmez, silinmiş kayıt upsert ile geri getirilmez.
+var upsertProtectedColumns = map[string]bool{
+	"created_at":        true,
+	"created_by":        true,
+	"deleted_at":        true,
+	"deleted_by":        true,
+	models.TenantColumn: true,
+}
+
+// Upsert kaydı ekler; çakışan kayıt varsa opts'a göre günceller ya da bırakır.
+// Sürümlü modellerde güncellenen kaydın sürümü bir artar.
+func (r *BaseRepository[T]) Upsert(ctx context.Context, entity *T, opts UpsertOptions) error {
+	if err := r.stampTenant(ctx, entity); err != nil {
+		return err
+	}
+	onConflict, err := r.onConflict(ctx, opts)
+	if err != nil {
+		return err
+	}
+	return translateError(txmanager.DB(ctx, r.db).Clauses(onConflict).Create(entity).Error)
+}
+
+// BulkUpsert kayıtları tek sorguda ekler ya da günceller ve etkilenen satır sayısını
+// döner. Sayı sürücüye göre değişir: MySQL güncellenen her satırı iki sayar,
+// DoNothing ile atlanan satırlar hiçbir sürücüde sayılmaz. Aynı sorguda aynı
+// çakışma değerine sahip iki kayıt olmamalıdır.
+func (r *BaseRepository[T]) BulkUpsert(ctx context.Context, entities []T, opts UpsertOptions) (int64, error) {
+	if len(entities) == 0 {
+		return 0, nil
+	}
+	for i := range entities {
+		if err := r.stampTenant(ctx, &entities[i]); err != nil {
+			return 0, err
+		}
+	}
+	onConflict, err := r.onConflict(ctx, opts)
+	if err != nil {
+		return 0, err
+	}
+	result := txmanager.DB(ctx, r.db).Clauses(onConflict).Create(&entities)
+	return result.RowsAffected, translateError(result.Error)
+}
+
+// onConflict upsert seçeneklerini ON CONFLICT yan tümcesine çevirir. Kiracı ya da
+// sahiplik kapsamı varsa güncelleme yalnızca kapsamdaki kayıtlarda yapılır; kapsam
+// dışındaki çakışan kayıt değişmeden kalır.
+func (r *BaseRepository[T]) onConflict(ctx context.Context, opts UpsertOptions) (clause.OnConflict, error) {
+	s, err := r.modelSchema()
+	if err != nil {
+		return clause.OnConflict{}, err
+	}
+
+	conflict := opts.Conflict
+	if len(conflict) == 0 {
+		conflict = s.PrimaryFieldDBNames
+	}
+	onConflict := clause.OnConflict{DoNothing: opts.DoNothing}
+	for _, column := range conflict {
+		field := s.LookUpField(column)
+		if field == nil {
+			return onConflict, apperrors.Internal("upsert çakışma sütunu bulunamadı: "+column, nil)
+		}
+		onConflict.Columns = append(onConflict.Columns, clause.Column{Name: field.DBName})
+	}
+	if opts.DoNothing {
+		return onConflict, nil
+	}
+
+	update := opts.Update
+	if len(update) == 0 {
+		update = defaultUpsertColumns(s, onConflict.Columns)
+	}
+	for _, column := range update {
+		field := s.LookUpField(column)
+		if field == nil || !field.Updatable {
+			return onConflict, apperrors.Internal("upsert güncelleme sütunu bulunamadı: "+column, nil)
+		}
+		if r.versioned && field.DBName == models.VersionColumn {
+			continue
+		}
+		onConflict.DoUpdates = append(onConflict.DoUpdates, clause.Assignment{
+			Column: clause.Column{Name: field.DBName},
+			Value:  clause.Column{Table: "excluded", Name: field.DBName},
+		})
+	}
+	if r.versioned {
+		onConflict.DoUpdates = append(onConflict.DoUpdates, clause.Assignment{
+			Column: clause.Column{Name: models.VersionColumn},
+			Value:  gorm.Expr("? + 1", clause.Column{Table: clause.CurrentTable, Name: models.VersionColumn}),
+		})
+	}
+
+	conditions, err := r.scopeConditions(ctx)
+	if err != nil {
+		return onConflict, err
+	}
+	if len(conditions) > 0 {
+		// MySQL'in ON DUPLICATE KEY UPDATE yan tümcesi koşul almaz; kapsam dışındaki
+		// kaydın üzerine yazılmaması için kapsamlı upsert reddedilir.
+		if r.db.Dialector.Name() == "mysql" {
+			return onConflict, apperrors.Internal("MySQL'de kiracı ya da sahiplik kapsamlı upsert desteklenmiyor", nil)
+		}
+		onConflict.Where = clause.Where{Exprs: conditions}
+	}
+	return onConflict, nil
+}
+
+func defaultUpsertColumns(s *schema.Schema, conflict []clause.Column) []string {
+	skip := make(map[string]bool, len(conflict))
+	for _, column := range conflict {
+		skip[column.Name] = true
+	}
+	var columns []string
+	for _, field := range s.Fields {
+		if field.DBName == "" || field.PrimaryKey || !field.Creatable || !field.Updatable ||
+			skip[field.DBName] || upsertProtectedColumns[field.DBName] {
+			continue
+		}
+		columns = append(columns, field.DBName)
+	}
+	return columns
+}
@@ -0,0 +1,177 @@
+package repositories
+
+import (
+	"context"
+
+	"zatrano/configs/databaseconfig"
+	"zatrano/models"
+	"zatrano/pkg/apperrors"
+	"zatrano/pkg/txmanager"
+
+	"gorm.io/gorm"
+)
+
+var ErrHierarchyCycle = apperrors.Validation("kullanıcı kendi alt kullanıcısının altına taşınamaz", "parent_id")
+
+type IUserHierarchyRepository interface {
+	AttachUser(ctx context.Context, userID uint, parentID *uint) error
+	MoveUser(ctx context.Context, userID uint, newParentID *uint) error
+	DetachUser(ctx context.Context, userID uint) error
+	GetDescendantIDs(ctx context.Context, userID uint) ([]uint, error)
+	GetDescendants(ctx context.Context, userID uint) ([]models.User, error)
+	IsDescendant(ctx context.Context, ancestorID, userID uint) (bool, error)
+	GetTeam(ctx context.Context, supervisorID uint) ([]models.TeamMember, error)
+}
+
+// UserHierarchyRepository kullanıcıları okuyan sorgularda kullanıcı deposunun
+// kiracı ve sahiplik kapsamlarını kullanır; hiyerarşi tablosu kapsamsızdır.
+type UserHierarchyRepository struct {
+	db    *gorm.DB
+	users *BaseRepository[models.User]
+}
+
+func NewUserHierarchyRepository() IUserHierarchyRepository {
+	db := databaseconfig.GetDB()
+	return &UserHierarchyRepository{db: db, users: NewBaseRepository[models.User](db)}
+}
+
+func (r *UserHierarchyRepository) AttachUser(ctx context.Context, userID uint, parentID *uint) error {
+	err := txmanager.DB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		return attachSubtree(tx, userID, parentID, true)
+	})
+	return translateError(err)
+}
+
+func (r *UserHierarchyRepository) MoveUser(ctx context.Context, userID uint, newParentID *uint) error {
+	err := txmanager.DB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		subtree, err := subtreeIDs(tx, userID)
+		if err != nil {
+			return err
+		}
+		if newParentID != nil {
+			for _, id := range subtree {
+				if id == *newParentID {
+					return ErrHierarchyCycle
+				}
+			}
+		}
+
+		if err := tx.Where("descendant_id IN ? AND ancestor_id NOT IN ?", subtree, subtree).
+			Delete(&models.UserHierarchy{}).Error; err != nil {
+			return err
+		}
+		return attachSubtree(tx, userID, newParentID, false)
+	})
+	return translateError(err)
+}
+
+func (r *UserHierarchyRepository) DetachUser(ctx context.Context, userID uint) error {
+	err := txmanager.DB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.Unscoped().Select("id", "parent_id").First(&user, userID).Error; err != nil {
+			return err
+		}
+
+		var childIDs []uint
+		if err := tx.Model(&models.UserHierarchy{}).
+			Where("ancestor_id = ? AND depth = 1", userID).
+			Pluck("descendant_id", &childIDs).Error; err != nil {
+			return err
+		}
+
+		// Silinen kullanıcının alt kullanıcıları onun üst kullanıcısına bağlanır;
+		// kullanıcının kendisine ait tüm bağlantılar kaldırılır.
+		for _, childID := range childIDs {
+			subtree, err := subtreeIDs(tx, childID)
+			if err != nil {
+				return err
+			}
+			if err := tx.Where("descendant_id IN ? AND ancestor_id NOT IN ?", subtree, subtree).
+				Delete(&models.UserHierarchy{}).Error; err != nil {
+				return err
+			}
+			if err := attachSubtree(tx, childID, user.ParentID, false); err != nil {
+				return err
+			}
+		}
+
+		return tx.Where("ancestor_id = ? OR descendant_id = ?", userID, userID).
+			Delete(&models.UserHierarchy{}).Error
+	})
+	return translateError(err)
+}
+
+func (r *UserHierarchyRepository) GetDescendantIDs(ctx context.Context, userID uint) ([]uint, error) {
+	var ids []uint
+	err := txmanager.DB(ctx, r.db).Model(&models.UserHierarchy{}).
+		Where("ancestor_id = ? AND depth > 0", userID).
+		Order("depth asc").
+		Pluck("descendant_id", &ids).Error
+	return ids, translateError(err)
+}
+
+func (r *UserHierarchyRepository) GetDescendants(ctx context.Context, userID uint) ([]models.User, error) {
+	var users []models.User
+	err := r.users.query(ctx).
+		Joins("JOIN user_hierarchies h ON h.descendant_id = users.id").
+		Where("h.ancestor_id = ? AND h.depth > 0", userID).
+		Order("h.depth asc, users.name asc").
+		Find(&users).Error
+	return users, translateError(err)
+}
+
+func (r *UserHierarchyRepository) IsDescendant(ctx context.Context, ancestorID, userID uint) (bool, error) {
+	var count int64
+	err := txmanager.DB(ctx, r.db).Model(&models.UserHierarchy{}).
+		Where("ancestor_id = ? AND descendant_id = ? AND depth > 0", ancestorID, userID).
+		Count(&count).Error
+	return count > 0, translateError(err)
+}
+
+func (r *UserHierarchyRepository) GetTeam(ctx context.Context, supervisorID uint) ([]models.TeamMember, error) {
+	var members []models.TeamMember
+	err := r.users.query(ctx).
+		Select(`users.id, users.name, users.account, users.status, users.type,
+			(SELECT COUNT(*) FROM user_hierarchies d WHERE d.ancestor_id = users.id AND d.depth > 0) AS team_size`).
+		Joins("JOIN user_hierarchies h ON h.descendant_id = users.id").
+		Where("h.ancestor_id = ? AND h.depth = 1", supervisorID).
+		Order("users.name asc").
+		Scan(&members).Error
+	return members, translateError(err)
+}
+
+func subtreeIDs(tx *gorm.DB, userID uint) ([]uint, error) {
+	var ids []uint
+	err := tx.Model(&models.UserHierarchy{}).
+		Where("ancestor_id = ?", userID).
+		Pluck("descendant_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		ids = []uint{userID}
+	}
+	return ids, nil
+}
+
+func attachSubtree(tx *gorm.DB, userID uint, parentID *uint, insertSelf bool) error {
+	if insertSelf {
+		if err := tx.Create(&models.UserHierarchy{AncestorID: userID, DescendantID: userID, Depth: 0}).Error; err != nil {
+			return err
+		}
+	}
+
+	if parentID != nil {
+		err := tx.Exec(`INSERT INTO user_hierarchies (ancestor_id, descendant_id, depth)
+			SELECT p.ancestor_id, s.descendant_id, p.depth + s.depth + 1
+			FROM user_hierarchies p CROSS JOIN user_hierarchies s
+			WHERE p.descendant_id = ? AND s.ancestor_id = ?`, *parentID, userID).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Model(&models.User{}).Where("id = ?", userID).UpdateColumn("parent_id", parentID).Error
+}
+
+var _ IUserHierarchyRepository = (*UserHierarchyRepository)(nil)
@@ -2,41 +2,91 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"zatrano/configs/databaseconfig"
 	"zatrano/models"
 	"zatrano/pkg/queryparams"
+	"zatrano/pkg/txmanager"
+
+	"gorm.io/gorm"
 )
 
 type IUserRepository interface {
-	GetAllUsers(params queryparams.ListParams) ([]models.User, int64, error)
-	GetUserByID(id uint) (*models.User, error)
+	GetAllUsers(ctx context.Context, params queryparams.ListParams) ([]models.User, int64, error)
+	GetUsersByCursor(ctx context.Context, params queryparams.ListParams) ([]models.User, queryparams.CursorMeta, error)
+	SuggestUsers(ctx context.Context, search string, limit int) ([]string, error)
+	GetUserByID(ctx context.Context, id uint) (*models.User, error)
 	CreateUser(ctx context.Context, user *models.User) error
 	BulkCreateUsers(ctx context.Context, users []models.User) error
-	UpdateUser(ctx context.Context, id uint, data map[string]interface{}, updatedBy uint) error
-	BulkUpdateUsers(ctx context.Context, condition map[string]interface{}, data map[string]interface{}, updatedBy uint) error
+	UpsertUser(ctx context.Context, user *models.User, opts UpsertOptions) error
+	BulkUpsertUsers(ctx context.Context, users []models.User, opts UpsertOptions) (int64, error)
+	UpdateUser(ctx context.Context, id uint, patch models.UserPatch, updatedBy uint) error
+	BulkUpdateUsers(ctx context.Context, condition map[string]interface{}, patch models.UserPatch, updatedBy uint) (int64, error)
+	SetUserPassword(ctx context.Context, id uint, passwordHash string, updatedBy uint) error
+	SetVerifiedEmail(ctx context.Context, id uint, email string, updatedBy uint) error
+	ActivateInvitedUser(ctx context.Context, id uint, passwordHash string, updatedBy uint) error
 	DeleteUser(ctx context.Context, id uint) error
-	BulkDeleteUsers(ctx context.Context, condition map[string]interface{}) error
-	GetUserCount() (int64, error)
+	BulkDeleteUsers(ctx context.Context, condition map[string]interface{}) (int64, error)
+	GetUserCount(ctx context.Context) (int64, error)
+	GetFilteredUserCount(ctx context.Context, params queryparams.ListParams) (int64, error)
+	StreamUsers(ctx context.Context, params queryparams.ListParams, fn func(user *models.User) error) error
+	UsersInBatches(ctx context.Context, params queryparams.ListParams, opts BatchOptions, fn func(ctx context.Context, users []models.User) error) error
+	EachUser(ctx context.Context, params queryparams.ListParams, opts BatchOptions, fn func(ctx context.Context, user *models.User) error) error
+	IsAccountKeyTaken(ctx context.Context, accountKey string, excludeID uint) (bool, error)
+	IsEmailTaken(ctx context.Context, email string, excludeID uint) (bool, error)
 }
 
 type UserRepository struct {
+	db   *gorm.DB
 	base IBaseRepository[models.User]
 }
 
 func NewUserRepository() IUserRepository {
-	base := NewBaseRepository[models.User](databaseconfig.GetDB())
+	db := databaseconfig.GetDB()
+	base := NewBaseRepository[models.User](db)
 	base.SetAllowedSortColumns([]string{"id", "name", "account", "created_at", "status", "type"})
+	base.SetFilterSchema(userFilterSchema)
+
+	return &UserRepository{db: db, base: base}
+}
+
+var (
+	textOperators = []queryparams.Operator{queryparams.OpContains, queryparams.OpEq, queryparams.OpNe}
+	dateOperators = []queryparams.Operator{queryparams.OpEq, queryparams.OpGt, queryparams.OpGte, queryparams.OpLt, queryparams.OpLte, queryparams.OpBetween}
+)
+
+var userFilterSchema = FilterSchema{
+	"id":      {Type: FilterNumber, Operators: []queryparams.Operator{queryparams.OpEq, queryparams.OpIn}},
+	"name":    {Type: FilterString, Operators: textOperators},
+	"account": {Type: FilterString, Operators: textOperators},
+	"email":   {Type: FilterString, Operators: append(textOperators, queryparams.OpNull)},
+	"type": {
+		Type:      FilterString,
+		Operators: []queryparams.Operator{queryparams.OpEq, queryparams.OpNe, queryparams.OpIn},
+		Values:    []string{string(models.Dashboard), string(models.Panel)},
+	},
+	"status":            {Type: FilterBool, Operators: []queryparams.Operator{queryparams.OpEq}},
+	"parent_id":         {Type: FilterNumber, Operators: []queryparams.Operator{queryparams.OpEq, queryparams.OpIn, queryparams.OpNull}},
+	"email_verified_at": {Type: FilterDate, Operators: append(dateOperators, queryparams.OpNull)},
+	"created_at":        {Type: FilterDate, Operators: dateOperators},
+	"updated_at":        {Type: FilterDate, Operators: dateOperators},
+}
+
+func (r *UserRepository) GetAllUsers(ctx context.Context, params queryparams.ListParams) ([]models.User, int64, error) {
+	return r.base.GetAll(ctx, params)
+}
 
-	return &UserRepository{base: base}
+func (r *UserRepository) GetUsersByCursor(ctx context.Context, params queryparams.ListParams) ([]models.User, queryparams.CursorMeta, error) {
+	return r.base.GetCursorPage(ctx, params)
 }
 
-func (r *UserRepository) GetAllUsers(params queryparams.ListParams) ([]models.User, int64, error) {
-	return r.base.GetAll(params)
+func (r *UserRepository) SuggestUsers(ctx context.Context, search string, limit int) ([]string, error) {
+	return r.base.Suggest(ctx, search, limit)
 }
 
-func (r *UserRepository) GetUserByID(id uint) (*models.User, error) {
-	return r.base.GetByID(id)
+func (r *UserRepository) GetUserByID(ctx context.Context, id uint) (*models.User, error) {
+	return r.base.GetByID(ctx, id)
 }
 
 func (r *UserRepository) CreateUser(ctx context.Context, user *models.User) error {
@@ -47,24 +97,91 @@ func (r *UserRepository) BulkCreateUsers(ctx context.Context, users []models.Use
 	return r.base.BulkCreate(ctx, users)
 }
 
-func (r *UserRepository) UpdateUser(ctx context.Context, id uint, data map[string]interface{}, updatedBy uint) error {
-	return r.base.Update(ctx, id, data, updatedBy)
+func (r *UserRepository) UpsertUser(ctx context.Context, user *models.User, opts UpsertOptions) error {
+	return r.base.Upsert(ctx, user, opts)
+}
+
+func (r *UserRepository) BulkUpsertUsers(ctx context.Context, users []models.User, opts UpsertOptions) (int64, error) {
+	return r.base.BulkUpsert(ctx, users, opts)
+}
+
+func (r *UserRepository) UpdateUser(ctx context.Context, id uint, patch models.UserPatch, updatedBy uint) error {
+	return r.base.Update(ctx, id, PatchOf(patch), updatedBy)
+}
+
+func (r *UserRepository) BulkUpdateUsers(ctx context.Context, condition map[string]interface{}, patch models.UserPatch, updatedBy uint) (int64, error) {
+	return r.base.BulkUpdate(ctx, condition, PatchOf(patch), updatedBy)
+}
+
+// SetUserPassword yalnızca hash'lenmiş şifreyi yazar.
+func (r *UserRepository) SetUserPassword(ctx context.Context, id uint, passwordHash string, updatedBy uint) error {
+	patch := PatchFields(&models.User{Password: passwordHash}, "password").allow("password")
+	return r.base.Update(ctx, id, patch, updatedBy)
 }
 
-func (r *UserRepository) BulkUpdateUsers(ctx context.Context, condition map[string]interface{}, data map[string]interface{}, updatedBy uint) error {
-	return r.base.BulkUpdate(ctx, condition, data, updatedBy)
+// SetVerifiedEmail doğrulanmış e-posta adresini doğrulama zamanıyla birlikte yazar.
+func (r *UserRepository) SetVerifiedEmail(ctx context.Context, id uint, email string, updatedBy uint) error {
+	now := time.Now()
+	patch := PatchFields(&models.User{Email: &email, EmailVerifiedAt: &now}, "email", "email_verified_at").
+		allow("email", "email_verified_at")
+	return r.base.Update(ctx, id, patch, updatedBy)
+}
+
+// ActivateInvitedUser daveti kabul eden kullanıcının şifresini yazar ve hesabı açar;
+// davet bağlantısına erişmek e-posta adresini doğrulamış sayılır.
+func (r *UserRepository) ActivateInvitedUser(ctx context.Context, id uint, passwordHash string, updatedBy uint) error {
+	now := time.Now()
+	user := models.User{Password: passwordHash, Status: true, EmailVerifiedAt: &now}
+	patch := PatchFields(&user, "password", "status", "email_verified_at").allow("password", "email_verified_at")
+	return r.base.Update(ctx, id, patch, updatedBy)
 }
 
 func (r *UserRepository) DeleteUser(ctx context.Context, id uint) error {
 	return r.base.Delete(ctx, id)
 }
 
-func (r *UserRepository) BulkDeleteUsers(ctx context.Context, condition map[string]interface{}) error {
+func (r *UserRepository) BulkDeleteUsers(ctx context.Context, condition map[string]interface{}) (int64, error) {
 	return r.base.BulkDelete(ctx, condition)
 }
 
-func (r *UserRepository) GetUserCount() (int64, error) {
-	return r.base.GetCount()
+func (r *UserRepository) GetUserCount(ctx context.Context) (int64, error) {
+	return r.base.GetCount(ctx)
+}
+
+func (r *UserRepository) GetFilteredUserCount(ctx context.Context, params queryparams.ListParams) (int64, error) {
+	return r.base.GetFilteredCount(ctx, params)
+}
+
+func (r *UserRepository) StreamUsers(ctx context.Context, params queryparams.ListParams, fn func(user *models.User) error) error {
+	return r.base.Stream(ctx, params, fn)
+}
+
+func (r *UserRepository) UsersInBatches(ctx context.Context, params queryparams.ListParams, opts BatchOptions, fn func(ctx context.Context, users []models.User) error) error {
+	return r.base.InBatches(ctx, params, opts, fn)
+}
+
+func (r *UserRepository) EachUser(ctx context.Context, params queryparams.ListParams, opts BatchOptions, fn func(ctx context.Context, user *models.User) error) error {
+	return r.base.Each(ctx, params, opts, fn)
+}
+
+// Benzersizlik kontrolleri kiracı ve sahiplik filtrelerinden bağımsızdır; silinmiş
+// kayıtlar da benzersiz indekste yer tuttuğu için Unscoped ile sorgulanır.
+func (r *UserRepository) IsAccountKeyTaken(ctx context.Context, accountKey string, excludeID uint) (bool, error) {
+	return r.exists(ctx, "account_key = ?", accountKey, excludeID)
+}
+
+func (r *UserRepository) IsEmailTaken(ctx context.Context, email string, excludeID uint) (bool, error) {
+	return r.exists(ctx, "email = ?", email, excludeID)
+}
+
+func (r *UserRepository) exists(ctx context.Context, condition string, value interface{}, excludeID uint) (bool, error) {
+	var count int64
+	query := txmanager.DB(ctx, r.db).Unscoped().Model(&models.User{}).Where(condition, value)
+	if excludeID > 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+	err := query.Count(&count).Error
+	return count > 0, translateError(err)
 }
 
 var _ IUserRepository = (*UserRepository)(nil)
@@ -15,7 +15,13 @@ func registerAuthRoutes(app *fiber.App) {
 	authGroup.Get("/login", middlewares.GuestMiddleware, authHandler.ShowLogin)
 	authGroup.Post("/login", middlewares.GuestMiddleware, authHandler.Login)
 
+	authGroup.Get("/invitation/:token", middlewares.GuestMiddleware, authHandler.ShowInvitation)
+	authGroup.Post("/invitation/:token", middlewares.GuestMiddleware, authHandler.AcceptInvitation)
+
 	authGroup.Get("/logout", middlewares.AuthMiddleware, authHandler.Logout)
 	authGroup.Get("/profile", middlewares.AuthMiddleware, authHandler.Profile)
 	authGroup.Post("/profile/update-password", middlewares.AuthMiddleware, authHandler.UpdatePassword)
+	authGroup.Post("/profile/email", middlewares.AuthMiddleware, authHandler.RequestEmailChange)
+
+	authGroup.Get("/email/confirm/:token", authHandler.ConfirmEmailChange)
 }
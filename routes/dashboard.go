@@ -14,6 +14,7 @@ func registerDashboardRoutes(app *fiber.App) {
 		middlewares.AuthMiddleware,
 		middlewares.StatusMiddleware,
 		middlewares.TypeMiddleware(models.Dashboard),
+		middlewares.OwnershipMiddleware,
 	)
 
 	dashboardHomeHandler := handlers.NewDashboardHomeHandler()
@@ -21,8 +22,13 @@ func registerDashboardRoutes(app *fiber.App) {
 
 	userHandler := handlers.NewUserHandler()
 	dashboardGroup.Get("/users", userHandler.ListUsers)
-	dashboardGroup.Get("/users/create", userHandler.ShowCreateUser)
-	dashboardGroup.Post("/users/create", userHandler.CreateUser)
+	dashboardGroup.Get("/users/tree", userHandler.ShowUserTree)
+	dashboardGroup.Get("/users/export", userHandler.ExportUsers)
+	dashboardGroup.Get("/users/exports/:id", userHandler.DownloadExport)
+	dashboardGroup.Get("/users/invite", userHandler.ShowInviteUser)
+	dashboardGroup.Post("/users/invite", userHandler.InviteUser)
+	dashboardGroup.Post("/users/invitations/:id/resend", userHandler.ResendInvitation)
+	dashboardGroup.Post("/users/invitations/:id/revoke", userHandler.RevokeInvitation)
 	dashboardGroup.Get("/users/update/:id", userHandler.ShowUpdateUser)
 	dashboardGroup.Post("/users/update/:id", userHandler.UpdateUser)
 	dashboardGroup.Delete("/users/delete/:id", userHandler.DeleteUser)
@@ -14,7 +14,9 @@ func registerPanelRoutes(app *fiber.App) {
 		middlewares.AuthMiddleware,
 		middlewares.StatusMiddleware,
 		middlewares.TypeMiddleware(models.Panel),
+		middlewares.OwnershipMiddleware,
 	)
 
-	panelGroup.Get("/home", handlers.PanelHomeHandler)
+	panelHomeHandler := handlers.NewPanelHomeHandler()
+	panelGroup.Get("/home", panelHomeHandler.HomePage)
 }
@@ -2,6 +2,7 @@ package routes
 
 import (
 	"zatrano/configs/sessionconfig"
+	"zatrano/middlewares"
 	"zatrano/models"
 
 	"github.com/gofiber/fiber/v2"
@@ -17,6 +18,9 @@ func SetupRoutes(app *fiber.App, db *gorm.DB) {
 		c.Locals("session", sessionStore)
 		return c.Next()
 	})
+	app.Use(middlewares.DeadlineMiddleware())
+	app.Use(middlewares.ReadYourWritesMiddleware)
+	app.Use(middlewares.TenantMiddleware)
 
 	registerAuthRoutes(app)
 	registerDashboardRoutes(app)
@@ -1,39 +1,36 @@
 package services
 
 import (
+	"context"
+	"errors"
+
 	"zatrano/configs/logconfig"
 	"zatrano/models"
+	"zatrano/pkg/apperrors"
 	"zatrano/repositories"
 
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
-	"gorm.io/gorm"
 )
 
-type ServiceError string
-
-func (e ServiceError) Error() string {
-	return string(e)
-}
-
-const (
-	ErrInvalidCredentials       ServiceError = "geçersiz kimlik bilgileri"
-	ErrUserNotFound             ServiceError = "kullanıcı bulunamadı"
-	ErrUserInactive             ServiceError = "kullanıcı aktif değil"
-	ErrCurrentPasswordIncorrect ServiceError = "mevcut şifre hatalı"
-	ErrPasswordTooShort         ServiceError = "yeni şifre en az 6 karakter olmalıdır"
-	ErrPasswordSameAsOld        ServiceError = "yeni şifre mevcut şifre ile aynı olamaz"
-	ErrAuthGeneric              ServiceError = "kimlik doğrulaması sırasında bir hata oluştu"
-	ErrProfileGeneric           ServiceError = "profil bilgileri alınırken hata"
-	ErrUpdatePasswordGeneric    ServiceError = "şifre güncellenirken bir hata oluştu"
-	ErrHashingFailed            ServiceError = "yeni şifre oluşturulurken hata"
-	ErrDatabaseUpdateFailed     ServiceError = "veritabanı güncellemesi başarısız oldu"
+var (
+	ErrInvalidCredentials       = apperrors.Unauthorized("geçersiz kimlik bilgileri")
+	ErrUserNotFound             = apperrors.NotFound("kullanıcı bulunamadı")
+	ErrUserInactive             = apperrors.Forbidden("kullanıcı aktif değil")
+	ErrCurrentPasswordIncorrect = apperrors.Validation("mevcut şifre hatalı", "current_password")
+	ErrPasswordTooShort         = apperrors.Validation("yeni şifre en az 6 karakter olmalıdır", "new_password")
+	ErrPasswordSameAsOld        = apperrors.Validation("yeni şifre mevcut şifre ile aynı olamaz", "new_password")
+	ErrAuthGeneric              = apperrors.Internal("kimlik doğrulaması sırasında bir hata oluştu", nil)
+	ErrProfileGeneric           = apperrors.Internal("profil bilgileri alınırken hata", nil)
+	ErrUpdatePasswordGeneric    = apperrors.Internal("şifre güncellenirken bir hata oluştu", nil)
+	ErrHashingFailed            = apperrors.Internal("yeni şifre oluşturulurken hata", nil)
+	ErrDatabaseUpdateFailed     = apperrors.Internal("veritabanı güncellemesi başarısız oldu", nil)
 )
 
 type IAuthService interface {
-	Authenticate(account, password string) (*models.User, error)
-	GetUserProfile(id uint) (*models.User, error)
-	UpdatePassword(userID uint, currentPass, newPassword string) error
+	Authenticate(ctx context.Context, account, password string) (*models.User, error)
+	GetUserProfile(ctx context.Context, id uint) (*models.User, error)
+	UpdatePassword(ctx context.Context, userID uint, currentPass, newPassword string) error
 }
 
 type AuthService struct {
@@ -60,28 +57,28 @@ func (s *AuthService) logWarn(action string, fields ...zap.Field) {
 	logconfig.Log.Warn(action+" başarısız", fields...)
 }
 
-func (s *AuthService) getUserByAccount(account string) (*models.User, error) {
-	user, err := s.repo.FindUserByAccount(account)
+func (s *AuthService) getUserByAccount(ctx context.Context, account string) (*models.User, error) {
+	user, err := s.repo.FindUserByAccount(ctx, account)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
+		if errors.Is(err, repositories.ErrNotFound) {
 			s.logWarn("Kullanıcı bulunamadı", zap.String("account", account))
 			return nil, ErrUserNotFound
 		}
 		s.logDBError("Kullanıcı sorgulama", err, zap.String("account", account))
-		return nil, ErrAuthGeneric
+		return nil, ErrAuthGeneric.WithCause(err)
 	}
 	return user, nil
 }
 
-func (s *AuthService) getUserByID(id uint) (*models.User, error) {
-	user, err := s.repo.FindUserByID(id)
+func (s *AuthService) getUserByID(ctx context.Context, id uint) (*models.User, error) {
+	user, err := s.repo.FindUserByID(ctx, id)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
+		if errors.Is(err, repositories.ErrNotFound) {
 			s.logWarn("Kullanıcı bulunamadı", zap.Uint("user_id", id))
 			return nil, ErrUserNotFound
 		}
 		s.logDBError("Kullanıcı sorgulama", err, zap.Uint("user_id", id))
-		return nil, ErrProfileGeneric
+		return nil, ErrProfileGeneric.WithCause(err)
 	}
 	return user, nil
 }
@@ -98,8 +95,8 @@ func (s *AuthService) hashPassword(password string) (string, error) {
 	return string(hashedPassword), nil
 }
 
-func (s *AuthService) Authenticate(account, password string) (*models.User, error) {
-	user, err := s.getUserByAccount(account)
+func (s *AuthService) Authenticate(ctx context.Context, account, password string) (*models.User, error) {
+	user, err := s.getUserByAccount(ctx, account)
 	if err != nil {
 		return nil, err
 	}
@@ -124,12 +121,12 @@ func (s *AuthService) Authenticate(account, password string) (*models.User, erro
 	return user, nil
 }
 
-func (s *AuthService) GetUserProfile(id uint) (*models.User, error) {
-	return s.getUserByID(id)
+func (s *AuthService) GetUserProfile(ctx context.Context, id uint) (*models.User, error) {
+	return s.getUserByID(ctx, id)
 }
 
-func (s *AuthService) UpdatePassword(userID uint, currentPass, newPassword string) error {
-	user, err := s.getUserByID(userID)
+func (s *AuthService) UpdatePassword(ctx context.Context, userID uint, currentPass, newPassword string) error {
+	user, err := s.getUserByID(ctx, userID)
 	if err != nil {
 		return err
 	}
@@ -152,13 +149,13 @@ func (s *AuthService) UpdatePassword(userID uint, currentPass, newPassword strin
 	hashedPassword, err := s.hashPassword(newPassword)
 	if err != nil {
 		s.logDBError("Parola hashleme", err, zap.Uint("user_id", userID))
-		return ErrHashingFailed
+		return ErrHashingFailed.WithCause(err)
 	}
 
 	user.Password = hashedPassword
-	if err := s.repo.UpdateUser(user); err != nil {
+	if err := s.repo.UpdateUser(ctx, user); err != nil {
 		s.logDBError("Kullanıcı güncelleme", err, zap.Uint("user_id", userID))
-		return ErrDatabaseUpdateFailed
+		return ErrDatabaseUpdateFailed.WithCause(err)
 	}
 
 	logconfig.Log.Info("Parola başarıyla güncellendi", zap.Uint("user_id", userID))
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"zatrano/configs/envconfig"
+	"zatrano/configs/logconfig"
+	"zatrano/models"
+	"zatrano/pkg/apperrors"
+	"zatrano/pkg/mailer"
+	"zatrano/pkg/ownership"
+	"zatrano/pkg/signedtoken"
+	"zatrano/pkg/tenancy"
+	"zatrano/repositories"
+
+	"go.uber.org/zap"
+)
+
+const emailChangeTokenPurpose = "email_change"
+
+var (
+	ErrEmailUnchanged           = apperrors.Validation("yeni e-posta adresi mevcut adresle aynı", "email")
+	ErrEmailVerificationInvalid = apperrors.NotFound("e-posta doğrulama bağlantısı geçersiz")
+	ErrEmailVerificationExpired = apperrors.Conflict("e-posta doğrulama bağlantısının süresi dolmuş")
+)
+
+type IEmailVerificationService interface {
+	RequestEmailChange(ctx context.Context, userID uint, email string, baseURL string) error
+	ConfirmEmailChange(ctx context.Context, token string) (*models.User, error)
+}
+
+type EmailVerificationService struct {
+	repo     repositories.IEmailVerificationRepository
+	userRepo repositories.IUserRepository
+}
+
+func NewEmailVerificationService() IEmailVerificationService {
+	return &EmailVerificationService{
+		repo:     repositories.NewEmailVerificationRepository(),
+		userRepo: repositories.NewUserRepository(),
+	}
+}
+
+func EmailVerificationExpiry() time.Duration {
+	return time.Duration(envconfig.GetEnvAsInt("EMAIL_VERIFICATION_EXPIRY_HOURS", 24)) * time.Hour
+}
+
+// RequestEmailChange yeni adrese bir onay bağlantısı gönderir; adres ancak
+// bağlantı onaylandığında kullanıcıya yazılır.
+func (s *EmailVerificationService) RequestEmailChange(ctx context.Context, userID uint, email string, baseURL string) error {
+	email, err := ValidateEmail(email)
+	if err != nil {
+		return err
+	}
+
+	// Profil sayfasında sahiplik politikası kurulmaz; kullanıcı yalnızca oturumdaki
+	// kendi kaydını değiştirir.
+	ctx = ownership.WithBypass(ctx)
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if user.EmailAddress() == email {
+		return ErrEmailUnchanged
+	}
+	if err := ensureUserUnique(ctx, s.userRepo, "", email, userID); err != nil {
+		return err
+	}
+
+	if err := s.repo.ExpirePendingVerifications(ctx, userID); err != nil {
+		logconfig.Log.Error("Bekleyen e-posta doğrulamaları geçersiz kılınamadı", zap.Uint("user_id", userID), zap.Error(err))
+		return apperrors.Wrap(err, apperrors.KindInternal, "e-posta değişikliği başlatılamadı")
+	}
+
+	pendingHash, err := randomSecret()
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.KindInternal, "e-posta değişikliği başlatılamadı")
+	}
+	verification := &models.EmailVerification{
+		UserID:    userID,
+		Email:     email,
+		TokenHash: pendingHash,
+		ExpiresAt: time.Now().Add(EmailVerificationExpiry()),
+	}
+	if err := s.repo.CreateVerification(ctx, verification); err != nil {
+		logconfig.Log.Error("E-posta doğrulama kaydı oluşturulamadı", zap.Uint("user_id", userID), zap.Error(err))
+		return apperrors.Wrap(err, apperrors.KindInternal, "e-posta değişikliği başlatılamadı")
+	}
+
+	token, err := signedtoken.Sign(emailChangeTokenPurpose, verification.ID, verification.ExpiresAt)
+	if err != nil {
+		logconfig.Log.Error("E-posta doğrulama bağlantısı imzalanamadı", zap.Uint("verification_id", verification.ID), zap.Error(err))
+		return apperrors.Wrap(err, apperrors.KindInternal, "e-posta değişikliği başlatılamadı")
+	}
+
+	currentUserID, _ := ctx.Value(contextUserIDKey).(uint)
+	err = s.repo.UpdateVerificationTokenHash(ctx, verification.ID, signedtoken.Hash(token), currentUserID)
+	if err != nil {
+		logconfig.Log.Error("E-posta doğrulama kaydı güncellenemedi", zap.Uint("verification_id", verification.ID), zap.Error(err))
+		return apperrors.Wrap(err, apperrors.KindInternal, "e-posta değişikliği başlatılamadı")
+	}
+
+	link := strings.TrimRight(envconfig.GetEnvWithDefault("APP_URL", baseURL), "/") + "/auth/email/confirm/" + token
+	msg := mailer.Message{
+		To:      email,
+		Subject: "E-posta adresinizi doğrulayın",
+		Body: "Merhaba " + user.Name + ",\n\n" +
+			"Hesabınızın e-posta adresini bu adresle değiştirmek için aşağıdaki bağlantıyı kullanın:\n\n" +
+			link + "\n\n" +
+			"Bu bağlantı " + verification.ExpiresAt.Format("02.01.2006 15:04") + " tarihine kadar geçerlidir. " +
+			"Bu talebi siz yapmadıysanız bu e-postayı dikkate almayın.",
+	}
+	if err := mailer.Send(ctx, msg); err != nil {
+		logconfig.Log.Error("E-posta doğrulama mesajı gönderilemedi", zap.Uint("verification_id", verification.ID), zap.Error(err))
+		return apperrors.Wrap(err, apperrors.KindInternal, "doğrulama e-postası gönderilemedi")
+	}
+
+	logconfig.Log.Info("E-posta değişikliği için doğrulama gönderildi", zap.Uint("user_id", userID), zap.Uint("verification_id", verification.ID))
+	return nil
+}
+
+func (s *EmailVerificationService) ConfirmEmailChange(ctx context.Context, token string) (*models.User, error) {
+	verificationID, err := signedtoken.Verify(emailChangeTokenPurpose, token)
+	switch {
+	case errors.Is(err, signedtoken.ErrExpiredToken):
+		return nil, ErrEmailVerificationExpired
+	case err != nil:
+		return nil, ErrEmailVerificationInvalid
+	}
+
+	// Bağlantı imzalı olduğundan kiracı ve sahiplik filtreleri atlanır; onay oturum
+	// açmadan da yapılabilir.
+	ctx = ownership.WithBypass(tenancy.WithBypass(ctx))
+	verification, err := s.repo.FindVerificationByTokenHash(ctx, signedtoken.Hash(token))
+	if err != nil || verification.ID != verificationID {
+		return nil, ErrEmailVerificationInvalid
+	}
+	if !verification.IsPending() {
+		return nil, ErrEmailVerificationExpired
+	}
+
+	// Adres, bağlantı gönderildikten sonra başka bir hesap tarafından alınmış olabilir.
+	if err := ensureUserUnique(ctx, s.userRepo, "", verification.Email, verification.UserID); err != nil {
+		return nil, err
+	}
+
+	ctx = context.WithValue(ctx, contextUserIDKey, verification.UserID)
+	if err := s.repo.MarkVerificationConfirmed(ctx, verification.ID, verification.UserID); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrEmailVerificationExpired
+		}
+		logconfig.Log.Error("E-posta doğrulaması kaydedilemedi", zap.Uint("verification_id", verification.ID), zap.Error(err))
+		return nil, apperrors.Wrap(err, apperrors.KindInternal, "e-posta adresi doğrulanırken bir hata oluştu")
+	}
+
+	err = s.userRepo.SetVerifiedEmail(ctx, verification.UserID, verification.Email, verification.UserID)
+	if err != nil {
+		logconfig.Log.Error("Kullanıcının e-posta adresi güncellenemedi", zap.Uint("user_id", verification.UserID), zap.Error(err))
+		return nil, apperrors.Wrap(err, apperrors.KindInternal, "e-posta adresi güncellenirken bir hata oluştu")
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, verification.UserID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	logconfig.Log.Info("E-posta adresi doğrulandı ve güncellendi", zap.Uint("user_id", user.ID))
+	return user, nil
+}
+
+var _ IEmailVerificationService = (*EmailVerificationService)(nil)
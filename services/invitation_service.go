@@ -0,0 +1,300 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"zatrano/configs/envconfig"
+	"zatrano/configs/logconfig"
+	"zatrano/models"
+	"zatrano/pkg/apperrors"
+	"zatrano/pkg/mailer"
+	"zatrano/pkg/ownership"
+	"zatrano/pkg/passwordpolicy"
+	"zatrano/pkg/signedtoken"
+	"zatrano/pkg/tenancy"
+	"zatrano/repositories"
+
+	"go.uber.org/zap"
+)
+
+const invitationTokenPurpose = "user_invitation"
+
+var (
+	ErrInvitationNotFound    = apperrors.NotFound("davet bulunamadı")
+	ErrInvitationInvalid     = apperrors.NotFound("davet bağlantısı geçersiz")
+	ErrInvitationExpired     = apperrors.Conflict("davet bağlantısının süresi dolmuş")
+	ErrInvitationNotPending  = apperrors.Conflict("davet artık geçerli değil")
+	ErrInvitationEmailNeeded = apperrors.Validation("davet için e-posta adresi zorunludur", "email")
+	ErrInvitationDelivery    = apperrors.Internal("davet e-postası gönderilemedi", nil)
+)
+
+type IInvitationService interface {
+	InviteUser(ctx context.Context, user *models.User, email string, baseURL string) (*models.UserInvitation, error)
+	ResendInvitation(ctx context.Context, id uint, baseURL string) error
+	RevokeInvitation(ctx context.Context, id uint) error
+	GetInvitationByToken(ctx context.Context, token string) (*models.UserInvitation, error)
+	AcceptInvitation(ctx context.Context, token, password, confirmation string) error
+	GetLatestInvitations(ctx context.Context, userIDs []uint) (map[uint]*models.UserInvitation, error)
+}
+
+type InvitationService struct {
+	repo        repositories.IInvitationRepository
+	userRepo    repositories.IUserRepository
+	userService IUserService
+}
+
+func NewInvitationService() IInvitationService {
+	return &InvitationService{
+		repo:        repositories.NewInvitationRepository(),
+		userRepo:    repositories.NewUserRepository(),
+		userService: NewUserService(),
+	}
+}
+
+func InvitationExpiry() time.Duration {
+	return time.Duration(envconfig.GetEnvAsInt("INVITATION_EXPIRY_HOURS", 72)) * time.Hour
+}
+
+func (s *InvitationService) InviteUser(ctx context.Context, user *models.User, email string, baseURL string) (*models.UserInvitation, error) {
+	if strings.TrimSpace(email) == "" {
+		return nil, ErrInvitationEmailNeeded
+	}
+	email, err := ValidateEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	user.Email = &email
+
+	// Davet edilen kişi şifresini kendisi belirleyene kadar hesap pasif kalır ve
+	// kimsenin bilmediği rastgele bir şifreyle korunur.
+	placeholder, err := randomSecret()
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.KindInternal, "geçici şifre oluşturulamadı")
+	}
+	user.Status = false
+
+	// Gerçek token kaydın ID'sine bağlı olduğu için issueAndSend içinde üretilir;
+	// o ana kadar benzersiz indeksi sağlamak için rastgele bir değer yazılır.
+	pendingHash, err := randomSecret()
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.KindInternal, "davet bağlantısı oluşturulamadı")
+	}
+
+	// Kullanıcı ve davet kaydı birlikte oluşturulur; e-posta gönderimi işlemin dışında kalır.
+	var invitation *models.UserInvitation
+	err = WithinTx(ctx, func(ctx context.Context) error {
+		// İşlem yeniden denenirse önceki denemenin ID'si ve hashlenmiş şifresi kullanılmamalı.
+		user.ID = 0
+		user.Password = placeholder
+		if err := s.userService.CreateUser(ctx, user); err != nil {
+			return err
+		}
+		invitation = &models.UserInvitation{
+			UserID:    user.ID,
+			Email:     email,
+			TokenHash: pendingHash,
+			ExpiresAt: time.Now().Add(InvitationExpiry()),
+		}
+		if err := s.repo.CreateInvitation(ctx, invitation); err != nil {
+			logconfig.Log.Error("Davet kaydı oluşturulamadı", zap.Uint("user_id", user.ID), zap.Error(err))
+			return apperrors.Wrap(err, apperrors.KindInternal, "davet oluşturulurken bir hata oluştu")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.issueAndSend(ctx, invitation, user, baseURL); err != nil {
+		return invitation, err
+	}
+	return invitation, nil
+}
+
+func (s *InvitationService) ResendInvitation(ctx context.Context, id uint, baseURL string) error {
+	invitation, user, err := s.getManagedInvitation(ctx, id)
+	if err != nil {
+		return err
+	}
+	if status := invitation.Status(); status != models.InvitationPending && status != models.InvitationExpired {
+		return ErrInvitationNotPending
+	}
+	return s.issueAndSend(ctx, invitation, user, baseURL)
+}
+
+func (s *InvitationService) RevokeInvitation(ctx context.Context, id uint) error {
+	invitation, _, err := s.getManagedInvitation(ctx, id)
+	if err != nil {
+		return err
+	}
+	if invitation.Status() == models.InvitationAccepted || invitation.Status() == models.InvitationRevoked {
+		return ErrInvitationNotPending
+	}
+
+	currentUserID, _ := ctx.Value(contextUserIDKey).(uint)
+	now := time.Now()
+	patch := repositories.PatchFields(&models.UserInvitation{RevokedAt: &now}, "revoked_at")
+	if err := s.repo.UpdateInvitation(ctx, invitation.ID, patch, currentUserID); err != nil {
+		logconfig.Log.Error("Davet iptal edilemedi", zap.Uint("invitation_id", id), zap.Error(err))
+		return apperrors.Wrap(err, apperrors.KindInternal, "davet iptal edilirken bir hata oluştu")
+	}
+
+	logconfig.Log.Info("Davet iptal edildi", zap.Uint("invitation_id", id), zap.Uint("revoked_by", currentUserID))
+	return nil
+}
+
+func (s *InvitationService) GetInvitationByToken(ctx context.Context, token string) (*models.UserInvitation, error) {
+	invitationID, err := signedtoken.Verify(invitationTokenPurpose, token)
+	switch {
+	case errors.Is(err, signedtoken.ErrExpiredToken):
+		return nil, ErrInvitationExpired
+	case err != nil:
+		return nil, ErrInvitationInvalid
+	}
+
+	// Bağlantı imzalı olduğundan kiracı ve sahiplik filtreleri atlanır; kimlik
+	// doğrulamasız bu sayfada kiracı bağlamı henüz bilinmez.
+	ctx = ownership.WithBypass(tenancy.WithBypass(ctx))
+	invitation, err := s.repo.FindInvitationByTokenHash(ctx, signedtoken.Hash(token))
+	if err != nil || invitation.ID != invitationID {
+		return nil, ErrInvitationInvalid
+	}
+
+	switch invitation.Status() {
+	case models.InvitationPending:
+		return invitation, nil
+	case models.InvitationExpired:
+		return nil, ErrInvitationExpired
+	default:
+		return nil, ErrInvitationNotPending
+	}
+}
+
+func (s *InvitationService) AcceptInvitation(ctx context.Context, token, password, confirmation string) error {
+	invitation, err := s.GetInvitationByToken(ctx, token)
+	if err != nil {
+		return err
+	}
+	if err := passwordpolicy.ValidateWithConfirmation(password, confirmation); err != nil {
+		return err
+	}
+
+	hashed := models.User{}
+	if err := hashed.SetPassword(password); err != nil {
+		return apperrors.Wrap(err, apperrors.KindInternal, "şifre oluşturulurken hata oluştu")
+	}
+
+	// Kabul işlemini davet edilen kullanıcı kendisi yaptığı için değişiklikler onun adına kaydedilir.
+	ctx = context.WithValue(ownership.WithBypass(tenancy.WithBypass(ctx)), contextUserIDKey, invitation.UserID)
+
+	// Hesap etkinleştirilemezse davet de açık kalır; bağlantı yeniden kullanılabilir.
+	err = WithinTx(ctx, func(ctx context.Context) error {
+		err := s.repo.MarkInvitationAccepted(ctx, invitation.ID, invitation.UserID)
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrInvitationNotPending
+		}
+		if err != nil {
+			logconfig.Log.Error("Davet kabul edilemedi", zap.Uint("invitation_id", invitation.ID), zap.Error(err))
+			return apperrors.Wrap(err, apperrors.KindInternal, "davet kabul edilirken bir hata oluştu")
+		}
+
+		// Bağlantıya erişebilmiş olmak davet adresinin sahipliğini kanıtlar.
+		err = s.userRepo.ActivateInvitedUser(ctx, invitation.UserID, hashed.Password, invitation.UserID)
+		if err != nil {
+			logconfig.Log.Error("Davet edilen kullanıcı etkinleştirilemedi", zap.Uint("user_id", invitation.UserID), zap.Error(err))
+			return apperrors.Wrap(err, apperrors.KindInternal, "hesap etkinleştirilirken bir hata oluştu")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logconfig.Log.Info("Davet kabul edildi", zap.Uint("invitation_id", invitation.ID), zap.Uint("user_id", invitation.UserID))
+	return nil
+}
+
+func (s *InvitationService) GetLatestInvitations(ctx context.Context, userIDs []uint) (map[uint]*models.UserInvitation, error) {
+	invitations, err := s.repo.GetLatestInvitations(ctx, userIDs)
+	if err != nil {
+		logconfig.Log.Error("Davet durumları alınamadı", zap.Error(err))
+		return nil, apperrors.Wrap(err, apperrors.KindInternal, "davet durumları getirilirken bir hata oluştu")
+	}
+	return invitations, nil
+}
+
+// getManagedInvitation daveti, ait olduğu kullanıcıyı mevcut kiracı ve sahiplik
+// kapsamında bulabiliyorsa döner; böylece yöneticiler yalnızca erişebildikleri
+// kullanıcıların davetlerini yönetebilir.
+func (s *InvitationService) getManagedInvitation(ctx context.Context, id uint) (*models.UserInvitation, *models.User, error) {
+	invitation, err := s.repo.GetInvitationByID(ctx, id)
+	if err != nil {
+		return nil, nil, ErrInvitationNotFound
+	}
+	user, err := s.userRepo.GetUserByID(ctx, invitation.UserID)
+	if err != nil {
+		return nil, nil, ErrInvitationNotFound
+	}
+	return invitation, user, nil
+}
+
+func (s *InvitationService) issueAndSend(ctx context.Context, invitation *models.UserInvitation, user *models.User, baseURL string) error {
+	expiresAt := time.Now().Add(InvitationExpiry())
+	token, err := signedtoken.Sign(invitationTokenPurpose, invitation.ID, expiresAt)
+	if err != nil {
+		logconfig.Log.Error("Davet bağlantısı imzalanamadı", zap.Uint("invitation_id", invitation.ID), zap.Error(err))
+		return apperrors.Wrap(err, apperrors.KindInternal, "davet bağlantısı oluşturulamadı")
+	}
+
+	// Yeni token hash'i kaydedildiğinde önceki bağlantılar geçersiz olur.
+	now := time.Now()
+	currentUserID, _ := ctx.Value(contextUserIDKey).(uint)
+	sent := models.UserInvitation{
+		TokenHash:  signedtoken.Hash(token),
+		ExpiresAt:  expiresAt,
+		SentCount:  invitation.SentCount + 1,
+		LastSentAt: &now,
+	}
+	patch := repositories.PatchFields(&sent, "token_hash", "expires_at", "sent_count", "last_sent_at")
+	err = s.repo.UpdateInvitation(ctx, invitation.ID, patch, currentUserID)
+	if err != nil {
+		logconfig.Log.Error("Davet güncellenemedi", zap.Uint("invitation_id", invitation.ID), zap.Error(err))
+		return apperrors.Wrap(err, apperrors.KindInternal, "davet bağlantısı oluşturulamadı")
+	}
+
+	link := strings.TrimRight(envconfig.GetEnvWithDefault("APP_URL", baseURL), "/") + "/auth/invitation/" + token
+	msg := mailer.Message{
+		To:      invitation.Email,
+		Subject: "Hesabınızı etkinleştirin",
+		Body: "Merhaba " + user.Name + ",\n\n" +
+			"Sizin için bir hesap oluşturuldu. Şifrenizi belirlemek ve hesabınızı etkinleştirmek için aşağıdaki bağlantıyı kullanın:\n\n" +
+			link + "\n\n" +
+			"Bu bağlantı " + expiresAt.Format("02.01.2006 15:04") + " tarihine kadar geçerlidir ve yalnızca bir kez kullanılabilir.",
+	}
+	if err := mailer.Send(ctx, msg); err != nil {
+		logconfig.Log.Error("Davet e-postası gönderilemedi", zap.Uint("invitation_id", invitation.ID), zap.Error(err))
+		return ErrInvitationDelivery
+	}
+
+	logconfig.Log.Info("Davet gönderildi",
+		zap.Uint("invitation_id", invitation.ID),
+		zap.Uint("user_id", user.ID),
+		zap.Int("sent_count", invitation.SentCount+1),
+	)
+	return nil
+}
+
+func randomSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+var _ IInvitationService = (*InvitationService)(nil)
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"zatrano/configs/logconfig"
+	"zatrano/models"
+	"zatrano/pkg/apperrors"
+	"zatrano/repositories"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrTenantNotFound = apperrors.NotFound("kiracı bulunamadı")
+	ErrTenantInactive = apperrors.Forbidden("kiracı aktif değil")
+)
+
+type ITenantService interface {
+	GetAllTenants(ctx context.Context) ([]models.Tenant, error)
+	GetTenantByID(ctx context.Context, id uint) (*models.Tenant, error)
+	ResolveTenant(ctx context.Context, slug string) (*models.Tenant, error)
+}
+
+type TenantService struct {
+	repo repositories.ITenantRepository
+}
+
+func NewTenantService() ITenantService {
+	return &TenantService{repo: repositories.NewTenantRepository()}
+}
+
+func (s *TenantService) GetAllTenants(ctx context.Context) ([]models.Tenant, error) {
+	tenants, err := s.repo.GetAllTenants(ctx)
+	if err != nil {
+		logconfig.Log.Error("Kiracılar alınamadı", zap.Error(err))
+		return nil, apperrors.Wrap(err, apperrors.KindInternal, "kiracılar getirilirken bir hata oluştu")
+	}
+	return tenants, nil
+}
+
+func (s *TenantService) GetTenantByID(ctx context.Context, id uint) (*models.Tenant, error) {
+	tenant, err := s.repo.GetTenantByID(ctx, id)
+	if err != nil {
+		logconfig.Log.Warn("Kiracı bulunamadı", zap.Uint("tenant_id", id), zap.Error(err))
+		return nil, ErrTenantNotFound
+	}
+	return tenant, nil
+}
+
+func (s *TenantService) ResolveTenant(ctx context.Context, slug string) (*models.Tenant, error) {
+	slug = strings.ToLower(strings.TrimSpace(slug))
+	if slug == "" {
+		return nil, ErrTenantNotFound
+	}
+
+	tenant, err := s.repo.FindTenantBySlug(ctx, slug)
+	if err != nil {
+		if !errors.Is(err, repositories.ErrNotFound) {
+			logconfig.Log.Error("Kiracı çözümlenemedi", zap.String("slug", slug), zap.Error(err))
+		}
+		return nil, ErrTenantNotFound
+	}
+	if !tenant.Status {
+		return nil, ErrTenantInactive
+	}
+	return tenant, nil
+}
+
+var _ ITenantService = (*TenantService)(nil)
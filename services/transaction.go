@@ -0,0 +1,16 @@
+package services
+
+import (
+	"context"
+
+	"zatrano/configs/databaseconfig"
+	"zatrano/pkg/txmanager"
+)
+
+// WithinTx fn içinde aynı ctx ile çağrılan tüm repository işlemlerini tek bir
+// veritabanı işleminde toplar. İç içe çağrılar savepoint kullanır; serileştirme
+// hatası veya kilitlenmede en dıştaki işlem yeniden denenir, bu yüzden fn içinde
+// e-posta gönderimi gibi geri alınamayan yan etkiler olmamalıdır.
+func WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return txmanager.New(databaseconfig.GetDB()).WithinTx(ctx, fn)
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"zatrano/configs/logconfig"
+	"zatrano/models"
+	"zatrano/pkg/apperrors"
+	"zatrano/pkg/queryparams"
+	"zatrano/repositories"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrParentNotFound = apperrors.Validation("üst kullanıcı bulunamadı", "parent_id")
+	ErrParentIsSelf   = apperrors.Validation("kullanıcı kendisinin üst kullanıcısı olamaz", "parent_id")
+)
+
+type IUserHierarchyService interface {
+	GetSubordinateIDs(ctx context.Context, userID uint) ([]uint, error)
+	GetDescendants(ctx context.Context, userID uint) ([]models.User, error)
+	GetTeam(ctx context.Context, supervisorID uint) ([]models.TeamMember, error)
+	GetTree(ctx context.Context) ([]*models.UserTreeNode, error)
+	GetParentCandidates(ctx context.Context, userID uint) ([]models.User, error)
+	ValidateParent(ctx context.Context, userID uint, parentID *uint) error
+	AttachUser(ctx context.Context, userID uint, parentID *uint) error
+	MoveUser(ctx context.Context, userID uint, parentID *uint) error
+	DetachUser(ctx context.Context, userID uint) error
+}
+
+type UserHierarchyService struct {
+	repo     repositories.IUserHierarchyRepository
+	userRepo repositories.IUserRepository
+}
+
+func NewUserHierarchyService() IUserHierarchyService {
+	return &UserHierarchyService{
+		repo:     repositories.NewUserHierarchyRepository(),
+		userRepo: repositories.NewUserRepository(),
+	}
+}
+
+func (s *UserHierarchyService) GetSubordinateIDs(ctx context.Context, userID uint) ([]uint, error) {
+	ids, err := s.repo.GetDescendantIDs(ctx, userID)
+	if err != nil {
+		logconfig.Log.Error("Alt kullanıcılar alınamadı", zap.Uint("user_id", userID), zap.Error(err))
+		return nil, apperrors.Wrap(err, apperrors.KindInternal, "alt kullanıcılar getirilirken bir hata oluştu")
+	}
+	return ids, nil
+}
+
+func (s *UserHierarchyService) GetDescendants(ctx context.Context, userID uint) ([]models.User, error) {
+	users, err := s.repo.GetDescendants(ctx, userID)
+	if err != nil {
+		logconfig.Log.Error("Alt kullanıcılar alınamadı", zap.Uint("user_id", userID), zap.Error(err))
+		return nil, apperrors.Wrap(err, apperrors.KindInternal, "alt kullanıcılar getirilirken bir hata oluştu")
+	}
+	return users, nil
+}
+
+func (s *UserHierarchyService) GetTeam(ctx context.Context, supervisorID uint) ([]models.TeamMember, error) {
+	members, err := s.repo.GetTeam(ctx, supervisorID)
+	if err != nil {
+		logconfig.Log.Error("Ekip bilgisi alınamadı", zap.Uint("supervisor_id", supervisorID), zap.Error(err))
+		return nil, apperrors.Wrap(err, apperrors.KindInternal, "ekip bilgisi getirilirken bir hata oluştu")
+	}
+	return members, nil
+}
+
+func (s *UserHierarchyService) GetTree(ctx context.Context) ([]*models.UserTreeNode, error) {
+	params := queryparams.ListParams{SortBy: "name", OrderBy: "asc"}
+
+	nodes := make(map[uint]*models.UserTreeNode)
+	var ordered []*models.UserTreeNode
+	err := s.userRepo.StreamUsers(ctx, params, func(user *models.User) error {
+		node := &models.UserTreeNode{User: *user}
+		nodes[user.ID] = node
+		ordered = append(ordered, node)
+		return nil
+	})
+	if err != nil {
+		logconfig.Log.Error("Kullanıcı ağacı alınamadı", zap.Error(err))
+		return nil, apperrors.Wrap(err, apperrors.KindInternal, "kullanıcı ağacı getirilirken bir hata oluştu")
+	}
+
+	var roots []*models.UserTreeNode
+	for _, node := range ordered {
+		if node.User.ParentID != nil {
+			if parent, ok := nodes[*node.User.ParentID]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+	return roots, nil
+}
+
+func (s *UserHierarchyService) GetParentCandidates(ctx context.Context, userID uint) ([]models.User, error) {
+	excluded := map[uint]bool{}
+	if userID != 0 {
+		excluded[userID] = true
+		descendantIDs, err := s.GetSubordinateIDs(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range descendantIDs {
+			excluded[id] = true
+		}
+	}
+
+	params := queryparams.ListParams{SortBy: "name", OrderBy: "asc"}
+	var candidates []models.User
+	err := s.userRepo.StreamUsers(ctx, params, func(user *models.User) error {
+		if !excluded[user.ID] {
+			candidates = append(candidates, *user)
+		}
+		return nil
+	})
+	if err != nil {
+		logconfig.Log.Error("Üst kullanıcı seçenekleri alınamadı", zap.Error(err))
+		return nil, apperrors.Wrap(err, apperrors.KindInternal, "üst kullanıcı seçenekleri getirilirken bir hata oluştu")
+	}
+	return candidates, nil
+}
+
+func (s *UserHierarchyService) ValidateParent(ctx context.Context, userID uint, parentID *uint) error {
+	if parentID == nil {
+		return nil
+	}
+	if userID != 0 && *parentID == userID {
+		return ErrParentIsSelf
+	}
+	if _, err := s.userRepo.GetUserByID(ctx, *parentID); err != nil {
+		return ErrParentNotFound
+	}
+	if userID == 0 {
+		return nil
+	}
+
+	isDescendant, err := s.repo.IsDescendant(ctx, userID, *parentID)
+	if err != nil {
+		logconfig.Log.Error("Hiyerarşi kontrolü yapılamadı", zap.Uint("user_id", userID), zap.Error(err))
+		return apperrors.Wrap(err, apperrors.KindInternal, "hiyerarşi kontrolü yapılamadı")
+	}
+	if isDescendant {
+		return repositories.ErrHierarchyCycle
+	}
+	return nil
+}
+
+func (s *UserHierarchyService) AttachUser(ctx context.Context, userID uint, parentID *uint) error {
+	if err := s.repo.AttachUser(ctx, userID, parentID); err != nil {
+		logconfig.Log.Error("Kullanıcı hiyerarşiye eklenemedi", zap.Uint("user_id", userID), zap.Error(err))
+		return apperrors.Wrap(err, apperrors.KindInternal, "kullanıcı hiyerarşiye eklenemedi")
+	}
+	return nil
+}
+
+func (s *UserHierarchyService) MoveUser(ctx context.Context, userID uint, parentID *uint) error {
+	if err := s.ValidateParent(ctx, userID, parentID); err != nil {
+		return err
+	}
+	if err := s.repo.MoveUser(ctx, userID, parentID); err != nil {
+		if errors.Is(err, repositories.ErrHierarchyCycle) {
+			return err
+		}
+		logconfig.Log.Error("Kullanıcı hiyerarşide taşınamadı", zap.Uint("user_id", userID), zap.Error(err))
+		return apperrors.Wrap(err, apperrors.KindInternal, "kullanıcı hiyerarşide taşınamadı")
+	}
+	logconfig.Log.Info("Kullanıcı hiyerarşide taşındı", zap.Uint("user_id", userID), zap.Uintp("parent_id", parentID))
+	return nil
+}
+
+func (s *UserHierarchyService) DetachUser(ctx context.Context, userID uint) error {
+	if err := s.repo.DetachUser(ctx, userID); err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		logconfig.Log.Error("Kullanıcı hiyerarşiden çıkarılamadı", zap.Uint("user_id", userID), zap.Error(err))
+		return apperrors.Wrap(err, apperrors.KindInternal, "kullanıcı hiyerarşiden çıkarılamadı")
+	}
+	return nil
+}
+
+var _ IUserHierarchyService = (*UserHierarchyService)(nil)
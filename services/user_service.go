@@ -2,9 +2,13 @@ package services
 
 import (
 	"context"
-	"errors"
+	"io"
+	"net/mail"
+	"strings"
 	"zatrano/configs/logconfig"
 	"zatrano/models"
+	"zatrano/pkg/apperrors"
+	"zatrano/pkg/exporter"
 	"zatrano/pkg/queryparams"
 	"zatrano/repositories"
 
@@ -13,28 +17,60 @@ import (
 
 const contextUserIDKey = "user_id"
 
+var (
+	ErrAccountTaken = apperrors.Conflict("bu hesap adı zaten kullanılıyor", "account")
+	ErrEmailTaken   = apperrors.Conflict("bu e-posta adresi zaten kullanılıyor", "email")
+	ErrInvalidEmail = apperrors.Validation("geçersiz e-posta adresi", "email")
+	ErrUserModified = repositories.ErrConflict
+)
+
 type IUserService interface {
-	GetAllUsers(params queryparams.ListParams) (*queryparams.PaginatedResult, error)
-	GetUserByID(id uint) (*models.User, error)
+	GetAllUsers(ctx context.Context, params queryparams.ListParams) (*queryparams.PaginatedResult, error)
+	GetUsersByCursor(ctx context.Context, params queryparams.ListParams) (*queryparams.CursorResult, error)
+	GetUserByID(ctx context.Context, id uint) (*models.User, error)
+	GetUserHistory(ctx context.Context, id uint) ([]models.EntityHistory, error)
 	CreateUser(ctx context.Context, user *models.User) error
 	UpdateUser(ctx context.Context, id uint, userData *models.User) error
 	DeleteUser(ctx context.Context, id uint) error
-	GetUserCount() (int64, error)
+	GetUserCount(ctx context.Context) (int64, error)
+	GetFilteredUserCount(ctx context.Context, params queryparams.ListParams) (int64, error)
+	ExportUsers(ctx context.Context, params queryparams.ListParams, opts exporter.Options, w io.Writer) error
+}
+
+var UserExportColumns = []exporter.Column[models.User]{
+	{Key: "id", Header: "ID", Value: func(u *models.User, _ string) interface{} { return u.ID }},
+	{Key: "name", Header: "Ad Soyad", Value: func(u *models.User, _ string) interface{} { return u.Name }},
+	{Key: "account", Header: "Hesap", Value: func(u *models.User, _ string) interface{} { return u.Account }},
+	{Key: "email", Header: "E-posta", Value: func(u *models.User, _ string) interface{} { return u.EmailAddress() }},
+	{Key: "type", Header: "Kullanıcı Tipi", Value: func(u *models.User, _ string) interface{} { return string(u.Type) }},
+	{Key: "status", Header: "Durum", Value: func(u *models.User, locale string) interface{} { return exporter.FormatBool(u.Status, locale) }},
+	{Key: "created_at", Header: "Oluşturma Tarihi", Value: func(u *models.User, locale string) interface{} {
+		return exporter.FormatTime(u.CreatedAt, locale)
+	}},
+	{Key: "updated_at", Header: "Güncelleme Tarihi", Value: func(u *models.User, locale string) interface{} {
+		return exporter.FormatTime(u.UpdatedAt, locale)
+	}},
 }
 
 type UserService struct {
-	repo repositories.IUserRepository
+	repo      repositories.IUserRepository
+	history   repositories.IHistoryRepository
+	hierarchy IUserHierarchyService
 }
 
 func NewUserService() IUserService {
-	return &UserService{repo: repositories.NewUserRepository()}
+	return &UserService{
+		repo:      repositories.NewUserRepository(),
+		history:   repositories.NewHistoryRepository(),
+		hierarchy: NewUserHierarchyService(),
+	}
 }
 
-func (s *UserService) GetAllUsers(params queryparams.ListParams) (*queryparams.PaginatedResult, error) {
-	users, totalCount, err := s.repo.GetAllUsers(params)
+func (s *UserService) GetAllUsers(ctx context.Context, params queryparams.ListParams) (*queryparams.PaginatedResult, error) {
+	users, totalCount, err := s.repo.GetAllUsers(ctx, params)
 	if err != nil {
 		logconfig.Log.Error("Kullanıcılar alınamadı", zap.Error(err))
-		return nil, errors.New("kullanıcılar getirilirken bir hata oluştu")
+		return nil, apperrors.Wrap(err, apperrors.KindInternal, "kullanıcılar getirilirken bir hata oluştu")
 	}
 
 	result := &queryparams.PaginatedResult{
@@ -46,64 +82,247 @@ func (s *UserService) GetAllUsers(params queryparams.ListParams) (*queryparams.P
 			TotalPages:  queryparams.CalculateTotalPages(totalCount, params.PerPage),
 		},
 	}
+	if totalCount == 0 {
+		result.Suggestions = s.suggestUsers(ctx, params)
+	}
 	return result, nil
 }
 
-func (s *UserService) GetUserByID(id uint) (*models.User, error) {
-	user, err := s.repo.GetUserByID(id)
+func (s *UserService) GetUsersByCursor(ctx context.Context, params queryparams.ListParams) (*queryparams.CursorResult, error) {
+	users, meta, err := s.repo.GetUsersByCursor(ctx, params)
+	if err != nil {
+		logconfig.Log.Error("Kullanıcılar imleçle alınamadı", zap.Error(err))
+		return nil, apperrors.Wrap(err, apperrors.KindInternal, "kullanıcılar getirilirken bir hata oluştu")
+	}
+	result := &queryparams.CursorResult{Data: users, Meta: meta}
+	if len(users) == 0 && params.Cursor == "" {
+		result.Suggestions = s.suggestUsers(ctx, params)
+	}
+	return result, nil
+}
+
+const userSuggestionLimit = 5
+
+// suggestUsers sonuçsuz bir aramada "bunu mu demek istediniz" önerilerini döner;
+// öneriler alınamazsa liste önerisiz gösterilir.
+func (s *UserService) suggestUsers(ctx context.Context, params queryparams.ListParams) []string {
+	if params.Search == "" {
+		return nil
+	}
+	suggestions, err := s.repo.SuggestUsers(ctx, params.Search, userSuggestionLimit)
+	if err != nil {
+		logconfig.Log.Warn("Arama önerileri alınamadı", zap.String("search", params.Search), zap.Error(err))
+		return nil
+	}
+	return suggestions
+}
+
+func (s *UserService) GetUserByID(ctx context.Context, id uint) (*models.User, error) {
+	user, err := s.repo.GetUserByID(ctx, id)
 	if err != nil {
 		logconfig.Log.Warn("Kullanıcı bulunamadı", zap.Uint("user_id", id), zap.Error(err))
-		return nil, errors.New("kullanıcı bulunamadı")
+		return nil, userLookupError(err)
 	}
 	return user, nil
 }
 
+const userHistoryLimit = 100
+
+// GetUserHistory, kullanıcıya erişim yetkisi doğrulandıktan sonra son değişiklikleri döner.
+func (s *UserService) GetUserHistory(ctx context.Context, id uint) ([]models.EntityHistory, error) {
+	if _, err := s.repo.GetUserByID(ctx, id); err != nil {
+		return nil, userLookupError(err)
+	}
+	entries, err := s.history.GetEntityHistory(ctx, &models.User{}, id, userHistoryLimit)
+	if err != nil {
+		logconfig.Log.Error("Kullanıcı geçmişi alınamadı", zap.Uint("user_id", id), zap.Error(err))
+		return nil, apperrors.Wrap(err, apperrors.KindInternal, "kullanıcı geçmişi getirilirken bir hata oluştu")
+	}
+	return entries, nil
+}
+
 func (s *UserService) CreateUser(ctx context.Context, user *models.User) error {
 	if user.Password == "" {
-		return errors.New("şifre alanı boş olamaz")
+		return apperrors.Validation("şifre alanı boş olamaz", "password")
 	}
 	if err := user.SetPassword(user.Password); err != nil {
 		logconfig.Log.Error("Şifre oluşturulamadı", zap.Error(err))
-		return errors.New("şifre oluşturulurken hata oluştu")
+		return apperrors.Wrap(err, apperrors.KindInternal, "şifre oluşturulurken hata oluştu")
+	}
+	user.Account = strings.TrimSpace(user.Account)
+	user.AccountKey = models.AccountKey(user.Account)
+	if user.Email != nil {
+		email, err := ValidateEmail(*user.Email)
+		if err != nil {
+			return err
+		}
+		user.Email = &email
 	}
-	return s.repo.CreateUser(ctx, user)
+	if err := ensureUserUnique(ctx, s.repo, user.AccountKey, user.EmailAddress(), 0); err != nil {
+		return err
+	}
+	if err := s.hierarchy.ValidateParent(ctx, 0, user.ParentID); err != nil {
+		return err
+	}
+	return WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateUser(ctx, user); err != nil {
+			return err
+		}
+		return s.hierarchy.AttachUser(ctx, user.ID, user.ParentID)
+	})
 }
 
 func (s *UserService) UpdateUser(ctx context.Context, id uint, userData *models.User) error {
 	currentUserID, ok := ctx.Value(contextUserIDKey).(uint)
 	if !ok || currentUserID == 0 {
-		return errors.New("güncelleyen kullanıcı kimliği geçersiz")
+		return apperrors.Unauthorized("güncelleyen kullanıcı kimliği geçersiz")
 	}
 
-	_, err := s.repo.GetUserByID(id)
+	existing, err := s.repo.GetUserByID(ctx, id)
 	if err != nil {
-		return errors.New("kullanıcı bulunamadı")
+		return userLookupError(err)
+	}
+
+	account := strings.TrimSpace(userData.Account)
+	accountKey := models.AccountKey(account)
+	if err := ensureUserUnique(ctx, s.repo, accountKey, "", id); err != nil {
+		return err
 	}
 
-	updateData := map[string]interface{}{
-		"name":    userData.Name,
-		"account": userData.Account,
-		"status":  userData.Status,
-		"type":    userData.Type,
+	patch := models.UserPatch{
+		Name:       &userData.Name,
+		Account:    &account,
+		AccountKey: &accountKey,
+		Status:     &userData.Status,
+		Type:       &userData.Type,
+	}
+	if userData.Version > 0 {
+		patch.Version = &userData.Version
 	}
 
+	var passwordHash string
 	if userData.Password != "" {
 		hashed := models.User{}
 		if err := hashed.SetPassword(userData.Password); err != nil {
-			return errors.New("şifre oluşturulurken hata oluştu")
+			return apperrors.Wrap(err, apperrors.KindInternal, "şifre oluşturulurken hata oluştu")
 		}
-		updateData["password"] = hashed.Password
+		passwordHash = hashed.Password
 	}
 
-	return s.repo.UpdateUser(ctx, id, updateData, currentUserID)
+	return WithinTx(ctx, func(ctx context.Context) error {
+		if !sameParent(existing.ParentID, userData.ParentID) {
+			if err := s.hierarchy.MoveUser(ctx, id, userData.ParentID); err != nil {
+				return err
+			}
+		}
+		if err := s.repo.UpdateUser(ctx, id, patch, currentUserID); err != nil {
+			return err
+		}
+		if passwordHash == "" {
+			return nil
+		}
+		return s.repo.SetUserPassword(ctx, id, passwordHash, currentUserID)
+	})
 }
 
 func (s *UserService) DeleteUser(ctx context.Context, id uint) error {
-	return s.repo.DeleteUser(ctx, id)
+	return WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteUser(ctx, id); err != nil {
+			return err
+		}
+		return s.hierarchy.DetachUser(ctx, id)
+	})
+}
+
+// ValidateEmail adresi doğrulayıp karşılaştırmalarda kullanılan normalize edilmiş halini döner.
+func ValidateEmail(email string) (string, error) {
+	email = models.NormalizeEmail(email)
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return "", ErrInvalidEmail
+	}
+	return email, nil
+}
+
+func ensureUserUnique(ctx context.Context, repo repositories.IUserRepository, accountKey, email string, excludeID uint) error {
+	if accountKey != "" {
+		taken, err := repo.IsAccountKeyTaken(ctx, accountKey, excludeID)
+		if err != nil {
+			logconfig.Log.Error("Hesap adı benzersizliği kontrol edilemedi", zap.Error(err))
+			return apperrors.Wrap(err, apperrors.KindInternal, "hesap adı kontrol edilirken bir hata oluştu")
+		}
+		if taken {
+			return ErrAccountTaken
+		}
+	}
+	if email != "" {
+		taken, err := repo.IsEmailTaken(ctx, email, excludeID)
+		if err != nil {
+			logconfig.Log.Error("E-posta benzersizliği kontrol edilemedi", zap.Error(err))
+			return apperrors.Wrap(err, apperrors.KindInternal, "e-posta adresi kontrol edilirken bir hata oluştu")
+		}
+		if taken {
+			return ErrEmailTaken
+		}
+	}
+	return nil
+}
+
+func userLookupError(err error) error {
+	if apperrors.IsKind(err, apperrors.KindNotFound) {
+		return ErrUserNotFound.WithCause(err)
+	}
+	return apperrors.Wrap(err, apperrors.KindInternal, "kullanıcı getirilirken bir hata oluştu")
+}
+
+func sameParent(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
 }
 
-func (s *UserService) GetUserCount() (int64, error) {
-	return s.repo.GetUserCount()
+func (s *UserService) GetUserCount(ctx context.Context) (int64, error) {
+	return s.repo.GetUserCount(ctx)
+}
+
+func (s *UserService) GetFilteredUserCount(ctx context.Context, params queryparams.ListParams) (int64, error) {
+	return s.repo.GetFilteredUserCount(ctx, params)
+}
+
+func (s *UserService) ExportUsers(ctx context.Context, params queryparams.ListParams, opts exporter.Options, w io.Writer) error {
+	columns, err := exporter.SelectColumns(UserExportColumns, opts.Columns)
+	if err != nil {
+		return err
+	}
+
+	keys, headers := exporter.Headers(columns)
+	writer, err := exporter.NewWriter(opts.Format, w, keys, headers)
+	if err != nil {
+		return err
+	}
+
+	var rowCount int
+	err = s.repo.StreamUsers(ctx, params, func(user *models.User) error {
+		rowCount++
+		return writer.WriteRow(exporter.Values(columns, user, opts.Locale))
+	})
+	if err != nil {
+		logconfig.Log.Error("Kullanıcılar dışa aktarılamadı", zap.String("format", string(opts.Format)), zap.Error(err))
+		return apperrors.Wrap(err, apperrors.KindInternal, "kullanıcılar dışa aktarılırken bir hata oluştu")
+	}
+
+	if err := writer.Close(); err != nil {
+		logconfig.Log.Error("Dışa aktarma dosyası tamamlanamadı", zap.String("format", string(opts.Format)), zap.Error(err))
+		return apperrors.Wrap(err, apperrors.KindInternal, "dışa aktarma dosyası oluşturulamadı")
+	}
+
+	logconfig.Log.Info("Kullanıcılar dışa aktarıldı",
+		zap.String("format", string(opts.Format)),
+		zap.Strings("columns", keys),
+		zap.Int("rows", rowCount),
+	)
+	return nil
 }
 
 var _ IUserService = (*UserService)(nil)